@@ -4,6 +4,11 @@ import (
 	"github.com/gin-gonic/gin"
 	v1 "github.com/ibrat-muslim/booking-service/api/v1"
 	"github.com/ibrat-muslim/booking-service/config"
+	"github.com/ibrat-muslim/booking-service/pkg/keyring"
+	"github.com/ibrat-muslim/booking-service/pkg/payments"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
+	"github.com/ibrat-muslim/booking-service/pkg/ratelimit"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
 	"github.com/ibrat-muslim/booking-service/storage"
 
 	swaggerFiles "github.com/swaggo/files"     // swagger embed files
@@ -16,6 +21,10 @@ type RouterOptions struct {
 	Cfg      *config.Config
 	Storage  storage.StorageI
 	InMemory storage.InMemoryStorageI
+	Payments payments.PaymentProvider
+	Keys     *keyring.KeyRing
+	// RateLimits are not applied if nil
+	RateLimits *ratelimit.Rules
 }
 
 // @title           Swagger for blog api
@@ -34,38 +43,102 @@ func New(opt *RouterOptions) *gin.Engine {
 		Cfg:      opt.Cfg,
 		Storage:  opt.Storage,
 		InMemory: opt.InMemory,
+		Payments: opt.Payments,
+		Keys:     opt.Keys,
 	})
 
+	if opt.RateLimits != nil {
+		// Counters are kept in the in-memory storage, so that limits hold
+		// across replicas, or in process if there is none, e.g. in tests
+		var store ratelimit.Store = ratelimit.NewMemoryStore()
+		if opt.InMemory != nil {
+			store = opt.InMemory
+		}
+
+		limiter := ratelimit.New(store, "rate_limit_")
+		router.Use(handlerV1.RateLimitMiddleware(limiter, opt.RateLimits))
+	}
+
+	// apiKeyAuth is used by the routes integrations may call with an API key
+	apiKeyAuth := handlerV1.ScopedAuthMiddleware(utils.ScopeAPIKey)
+
 	router.Static("/media", "./media")
 
+	router.GET("/.well-known/jwks.json", handlerV1.GetJWKS)
+
 	apiV1 := router.Group("/v1")
 
 	apiV1.GET("/users/:id", handlerV1.GetUser)
 	apiV1.GET("/users/me", handlerV1.AuthMiddleware, handlerV1.GetUserProfile)
 	apiV1.GET("/users", handlerV1.GetUsers)
-	apiV1.POST("/users", handlerV1.AuthMiddleware, handlerV1.CreateUser)
-	apiV1.PUT("/users/:id", handlerV1.AuthMiddleware, handlerV1.UpdateUser)
-	apiV1.DELETE("users/:id", handlerV1.AuthMiddleware, handlerV1.DeleteUser)
+	apiV1.POST("/users", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.UserCreate), handlerV1.CreateUser)
+	apiV1.PUT("/users/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.UserUpdate), handlerV1.UpdateUser)
+	apiV1.DELETE("users/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.UserDelete), handlerV1.DeleteUser)
+	apiV1.POST("/users/:id/ban", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.UserBan), handlerV1.BanUser)
+	apiV1.DELETE("users/:id/ban", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.UserBan), handlerV1.UnbanUser)
 
 	// apiV1.GET("/categories/:id", handlerV1.GetCategory)
 	// apiV1.GET("/categories", handlerV1.GetCategories)
-	// apiV1.POST("/categories", handlerV1.AuthMiddleware, handlerV1.CreateCategory)
-	// apiV1.PUT("/categories/:id", handlerV1.AuthMiddleware, handlerV1.UpdateCategory)
-	// apiV1.DELETE("categories/:id", handlerV1.AuthMiddleware, handlerV1.DeleteCategory)
+	// apiV1.POST("/categories", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.CategoryWrite), handlerV1.CreateCategory)
+	// apiV1.PUT("/categories/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.CategoryWrite), handlerV1.UpdateCategory)
+	// apiV1.DELETE("categories/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.CategoryWrite), handlerV1.DeleteCategory)
 
 	// apiV1.GET("/posts/:id", handlerV1.GetPost)
 	// apiV1.GET("/posts", handlerV1.GetPosts)
-	// apiV1.POST("/posts", handlerV1.AuthMiddleware, handlerV1.CreatePost)
-	// apiV1.PUT("/posts/:id", handlerV1.AuthMiddleware, handlerV1.UpdatePost)
-	// apiV1.DELETE("posts/:id", handlerV1.AuthMiddleware, handlerV1.DeletePost)
+	// apiV1.POST("/posts", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PostCreate), handlerV1.CreatePost)
+	// apiV1.PUT("/posts/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PostUpdate), handlerV1.UpdatePost)
+	// apiV1.DELETE("posts/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PostDelete), handlerV1.DeletePost)
 
 	// apiV1.GET("/comments", handlerV1.GetComments)
-	// apiV1.POST("/comments", handlerV1.AuthMiddleware, handlerV1.CreateComment)
-	// apiV1.PUT("/comments/:id", handlerV1.AuthMiddleware, handlerV1.UpdateComment)
-	// apiV1.DELETE("comments/:id", handlerV1.AuthMiddleware, handlerV1.DeleteComment)
+	// apiV1.POST("/comments", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.CommentCreate), handlerV1.CreateComment)
+	// apiV1.PUT("/comments/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.CommentUpdate), handlerV1.UpdateComment)
+	// apiV1.DELETE("comments/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.CommentDelete), handlerV1.DeleteComment)
 
 	// apiV1.GET("/likes/user-post", handlerV1.AuthMiddleware, handlerV1.GetLike)
-	// apiV1.POST("/likes", handlerV1.AuthMiddleware, handlerV1.CreateOrUpdateLike)
+	// apiV1.POST("/likes", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.LikeWrite), handlerV1.CreateOrUpdateLike)
+
+	apiV1.GET("/properties/:id", handlerV1.GetProperty)
+	apiV1.GET("/properties", handlerV1.GetProperties)
+	apiV1.POST("/properties", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyCreate), handlerV1.CreateProperty)
+	apiV1.PUT("/properties/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.UpdateProperty)
+	apiV1.DELETE("properties/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.DeleteProperty)
+
+	apiV1.GET("/properties/:id/reviews", handlerV1.GetPropertyReviews)
+	apiV1.POST("/reviews", handlerV1.AuthMiddleware, handlerV1.CreateReview)
+	apiV1.POST("/reviews/:id/reply", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.ReplyReview)
+
+	apiV1.GET("/properties/:id/rooms", handlerV1.GetRooms)
+	apiV1.POST("/properties/:id/rooms", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.CreateRoom)
+	apiV1.GET("/rooms/:id", handlerV1.GetRoom)
+	apiV1.PUT("/rooms/:id", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.UpdateRoom)
+	apiV1.DELETE("rooms/:id", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.DeleteRoom)
+
+	apiV1.GET("/rooms/:id/pricing-rules", handlerV1.GetPricingRules)
+	apiV1.POST("/rooms/:id/pricing-rules", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.CreatePricingRule)
+	apiV1.DELETE("pricing-rules/:id", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.DeletePricingRule)
+	apiV1.POST("/rooms/:id/quote", handlerV1.GetRoomQuote)
+
+	apiV1.GET("/availability", handlerV1.GetAvailability)
+	apiV1.GET("/rooms/:id/calendar", handlerV1.GetRoomCalendar)
+
+	apiV1.POST("/rooms/:id/calendar-token", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.CreateRoomCalendarToken)
+	apiV1.GET("/rooms/:id/calendar.ics", handlerV1.GetRoomCalendarFeed)
+	apiV1.POST("/rooms/:id/calendar/import", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.ImportRoomCalendar)
+
+	apiV1.GET("/rooms/:id/blocks", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.GetRoomBlocks)
+	apiV1.POST("/rooms/:id/blocks", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.CreateRoomBlock)
+	apiV1.DELETE("rooms/:id/blocks/:block_id", apiKeyAuth, handlerV1.RequirePermission(policy.PropertyManage), handlerV1.DeleteRoomBlock)
+
+	apiV1.GET("/bookings/:id", apiKeyAuth, handlerV1.RequirePermission(policy.BookingRead), handlerV1.GetBooking)
+	apiV1.GET("/bookings", apiKeyAuth, handlerV1.RequirePermission(policy.BookingRead), handlerV1.GetBookings)
+	apiV1.POST("/bookings", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.BookingCreate), handlerV1.CreateBooking)
+	apiV1.PUT("/bookings/:id/status", apiKeyAuth, handlerV1.RequirePermission(policy.BookingManage), handlerV1.UpdateBookingStatus)
+	apiV1.POST("/bookings/:id/cancel", handlerV1.AuthMiddleware, handlerV1.CancelBooking)
+	apiV1.POST("/bookings/:id/pay", handlerV1.AuthMiddleware, handlerV1.PayBooking)
+
+	apiV1.POST("/payments/webhook", handlerV1.PaymentWebhook)
+
+	apiV1.GET("/owner/reports", apiKeyAuth, handlerV1.RequirePermission(policy.ReportRead), handlerV1.GetOwnerReports)
 
 	apiV1.POST("/file-upload", handlerV1.AuthMiddleware, handlerV1.UploadFile)
 
@@ -74,7 +147,28 @@ func New(opt *RouterOptions) *gin.Engine {
 	apiV1.POST("/auth/login", handlerV1.Login)
 	apiV1.POST("/auth/forgot-password", handlerV1.ForgotPassword)
 	apiV1.POST("/auth/verify-forgot-password", handlerV1.VerfiyForgotPassword)
-	apiV1.POST("/auth/update-password", handlerV1.AuthMiddleware, handlerV1.UpdatePassword)
+	apiV1.POST("/auth/magic-link", handlerV1.RequestMagicLink)
+	apiV1.POST("/auth/magic-link/consume", handlerV1.ConsumeMagicLink)
+	apiV1.POST("/auth/update-password", handlerV1.ScopedAuthMiddleware(utils.ScopePasswordReset), handlerV1.UpdatePassword)
+	apiV1.GET("/auth/oidc/login", handlerV1.OIDCLogin)
+	apiV1.GET("/auth/oidc/callback", handlerV1.OIDCCallback)
+	apiV1.POST("/auth/refresh", handlerV1.RefreshToken)
+	apiV1.POST("/auth/logout", handlerV1.AuthMiddleware, handlerV1.Logout)
+	apiV1.GET("/auth/sessions", handlerV1.AuthMiddleware, handlerV1.GetSessions)
+	apiV1.DELETE("auth/sessions", handlerV1.AuthMiddleware, handlerV1.DeleteSessions)
+	apiV1.DELETE("auth/sessions/:id", handlerV1.AuthMiddleware, handlerV1.DeleteSession)
+
+	apiV1.GET("/api-keys", handlerV1.AuthMiddleware, handlerV1.GetAPIKeys)
+	apiV1.POST("/api-keys", handlerV1.AuthMiddleware, handlerV1.CreateAPIKey)
+	apiV1.DELETE("api-keys/:id", handlerV1.AuthMiddleware, handlerV1.DeleteAPIKey)
+
+	apiV1.GET("/auth/2fa", handlerV1.AuthMiddleware, handlerV1.GetTwoFactorStatus)
+	apiV1.POST("/auth/2fa/enroll", handlerV1.ScopedAuthMiddleware(utils.ScopeMFAEnroll), handlerV1.EnrollTwoFactor)
+	apiV1.POST("/auth/2fa/confirm", handlerV1.ScopedAuthMiddleware(utils.ScopeMFAEnroll), handlerV1.ConfirmTwoFactor)
+	apiV1.POST("/auth/2fa/disable", handlerV1.AuthMiddleware, handlerV1.DisableTwoFactor)
+	apiV1.POST("/auth/2fa/verify", handlerV1.VerifyTwoFactor)
+	apiV1.GET("/auth/2fa/policy", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.TwoFactorPolicyManage), handlerV1.GetTwoFactorPolicy)
+	apiV1.PUT("/auth/2fa/policy", handlerV1.AuthMiddleware, handlerV1.RequirePermission(policy.TwoFactorPolicyManage), handlerV1.UpdateTwoFactorPolicy)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
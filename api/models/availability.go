@@ -0,0 +1,37 @@
+package models
+
+type GetAvailabilityParams struct {
+	Limit    int32  `json:"limit" binding:"required" default:"10"`
+	Page     int32  `json:"page" binding:"required" default:"1"`
+	City     string `json:"city"`
+	CheckIn  string `json:"check_in" binding:"required" example:"2006-01-02"`
+	CheckOut string `json:"check_out" binding:"required" example:"2006-01-02"`
+	Guests   int32  `json:"guests" default:"1"`
+}
+
+type AvailableRoom struct {
+	Room
+	Quote *Quote `json:"quote"`
+}
+
+type AvailableProperty struct {
+	Property
+	AvailableRooms []*AvailableRoom `json:"available_rooms"`
+}
+
+type GetAvailabilityResponse struct {
+	Properties []*AvailableProperty `json:"properties"`
+	Count      int32                `json:"count"`
+}
+
+type RoomCalendarDay struct {
+	Date      string  `json:"date"`
+	Available bool    `json:"available"`
+	Price     float64 `json:"price"`
+}
+
+type RoomCalendarResponse struct {
+	RoomID int64              `json:"room_id"`
+	Month  string             `json:"month"`
+	Days   []*RoomCalendarDay `json:"days"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+type Booking struct {
+	ID           int64      `json:"id"`
+	RoomID       int64      `json:"room_id"`
+	GuestID      int64      `json:"guest_id"`
+	CheckIn      string     `json:"check_in"`
+	CheckOut     string     `json:"check_out"`
+	GuestsCount  int32      `json:"guests_count"`
+	TotalPrice   float64    `json:"total_price"`
+	Status       string     `json:"status"`
+	RefundAmount *float64   `json:"refund_amount"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    *time.Time `json:"updated_at"`
+}
+
+type CreateBookingRequest struct {
+	RoomID      int64  `json:"room_id" binding:"required"`
+	CheckIn     string `json:"check_in" binding:"required,datetime=2006-01-02"`
+	CheckOut    string `json:"check_out" binding:"required,datetime=2006-01-02"`
+	GuestsCount int32  `json:"guests_count" binding:"required,min=1"`
+}
+
+type UpdateBookingStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=checked_in completed no_show"`
+}
+
+type GetBookingsParams struct {
+	Limit      int32  `json:"limit" binding:"required" default:"10"`
+	Page       int32  `json:"page" binding:"required" default:"1"`
+	RoomID     int64  `json:"room_id"`
+	PropertyID int64  `json:"property_id"`
+	Status     string `json:"status" enums:"pending,confirmed,checked_in,completed,cancelled,no_show"`
+}
+
+type GetBookingsResponse struct {
+	Bookings []*Booking `json:"bookings"`
+	Count    int32      `json:"count"`
+}
@@ -0,0 +1,13 @@
+package models
+
+type RoomCalendarTokenResponse struct {
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
+
+type ImportRoomCalendarResponse struct {
+	Created   int32 `json:"created"`
+	Updated   int32 `json:"updated"`
+	Unchanged int32 `json:"unchanged"`
+	Removed   int32 `json:"removed"`
+}
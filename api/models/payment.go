@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type Payment struct {
+	ID             int64      `json:"id"`
+	BookingID      int64      `json:"booking_id"`
+	Amount         float64    `json:"amount"`
+	RefundedAmount float64    `json:"refunded_amount"`
+	Status         string     `json:"status"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+}
+
+type PayBookingRequest struct {
+	PaymentToken string `json:"payment_token" binding:"required"`
+}
+
+type PaymentEvent struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type" enums:"payment.captured,payment.refunded,payment.failed"`
+	PaymentID string  `json:"payment_id"`
+	Amount    float64 `json:"amount"`
+}
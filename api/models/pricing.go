@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+type PricingRule struct {
+	ID              int64     `json:"id"`
+	RoomID          int64     `json:"room_id"`
+	Type            string    `json:"type"`
+	StartDate       *string   `json:"start_date"`
+	EndDate         *string   `json:"end_date"`
+	DaysOfWeek      []int64   `json:"days_of_week"`
+	Price           *float64  `json:"price"`
+	Multiplier      *float64  `json:"multiplier"`
+	MinNights       *int32    `json:"min_nights"`
+	DiscountPercent *float64  `json:"discount_percent"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type CreatePricingRuleRequest struct {
+	Type            string   `json:"type" binding:"required,oneof=season weekday min_nights length_of_stay"`
+	StartDate       *string  `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate         *string  `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	DaysOfWeek      []int64  `json:"days_of_week" binding:"dive,min=0,max=6"`
+	Price           *float64 `json:"price" binding:"omitempty,gte=0"`
+	Multiplier      *float64 `json:"multiplier" binding:"omitempty,gt=0"`
+	MinNights       *int32   `json:"min_nights" binding:"omitempty,min=1"`
+	DiscountPercent *float64 `json:"discount_percent" binding:"omitempty,gte=0,lte=100"`
+}
+
+type GetPricingRulesResponse struct {
+	PricingRules []*PricingRule `json:"pricing_rules"`
+}
+
+type QuoteRequest struct {
+	CheckIn     string `json:"check_in" binding:"required,datetime=2006-01-02"`
+	CheckOut    string `json:"check_out" binding:"required,datetime=2006-01-02"`
+	GuestsCount int32  `json:"guests_count" binding:"required,min=1"`
+}
+
+type QuoteNight struct {
+	Date  string  `json:"date"`
+	Price float64 `json:"price"`
+}
+
+type Quote struct {
+	Nights          []*QuoteNight `json:"nights"`
+	Subtotal        float64       `json:"subtotal"`
+	DiscountPercent float64       `json:"discount_percent"`
+	Discount        float64       `json:"discount"`
+	Total           float64       `json:"total"`
+}
+
+type QuoteResponse struct {
+	RoomID   int64  `json:"room_id"`
+	CheckIn  string `json:"check_in"`
+	CheckOut string `json:"check_out"`
+	Quote
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+type Property struct {
+	ID                 int64               `json:"id"`
+	OwnerID            int64               `json:"owner_id"`
+	Title              string              `json:"title"`
+	Description        string              `json:"description"`
+	Address            string              `json:"address"`
+	City               string              `json:"city"`
+	Latitude           float64             `json:"latitude"`
+	Longitude          float64             `json:"longitude"`
+	Amenities          []string            `json:"amenities"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy"`
+	RatingInfo         *PropertyRatingInfo `json:"rating_info"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          *time.Time          `json:"updated_at"`
+}
+
+type CancellationTier struct {
+	DaysBefore    int32   `json:"days_before" binding:"min=0"`
+	RefundPercent float64 `json:"refund_percent" binding:"min=0,max=100"`
+}
+
+type CancellationPolicy struct {
+	Type        string              `json:"type"`
+	Tiers       []*CancellationTier `json:"tiers"`
+	Description string              `json:"description"`
+}
+
+type CreatePropertyRequest struct {
+	Title              string              `json:"title" binding:"required,max=100"`
+	Description        string              `json:"description"`
+	Address            string              `json:"address" binding:"required"`
+	City               string              `json:"city" binding:"required,max=50"`
+	Latitude           float64             `json:"latitude" binding:"latitude"`
+	Longitude          float64             `json:"longitude" binding:"longitude"`
+	Amenities          []string            `json:"amenities" binding:"dive,required"`
+	CancellationPolicy string              `json:"cancellation_policy" binding:"omitempty,oneof=flexible moderate strict custom" default:"moderate"`
+	CancellationTiers  []*CancellationTier `json:"cancellation_tiers" binding:"required_if=CancellationPolicy custom,dive"`
+}
+
+type GetPropertiesParams struct {
+	Limit   int32  `json:"limit" binding:"required" default:"10"`
+	Page    int32  `json:"page" binding:"required" default:"1"`
+	Search  string `json:"search"`
+	City    string `json:"city"`
+	OwnerID int64  `json:"owner_id"`
+}
+
+type GetPropertiesResponse struct {
+	Properties []*Property `json:"properties"`
+	Count      int32       `json:"count"`
+}
@@ -0,0 +1,29 @@
+package models
+
+type GetReportParams struct {
+	From       string `json:"from" example:"2006-01-02"`
+	To         string `json:"to" example:"2006-01-02"`
+	PropertyID int64  `json:"property_id"`
+	Format     string `json:"format" enums:"json,csv" default:"json"`
+}
+
+type PropertyMonthReport struct {
+	PropertyID      int64   `json:"property_id"`
+	PropertyTitle   string  `json:"property_title"`
+	Month           string  `json:"month"`
+	AvailableNights int64   `json:"available_nights"`
+	BookedNights    int64   `json:"booked_nights"`
+	OccupancyRate   float64 `json:"occupancy_rate"`
+	Revenue         float64 `json:"revenue"`
+	ADR             float64 `json:"adr"`
+	RevPAR          float64 `json:"revpar"`
+	Bookings        int64   `json:"bookings"`
+	Cancellations   int64   `json:"cancellations"`
+	AverageLeadTime float64 `json:"average_lead_time"`
+}
+
+type GetReportResponse struct {
+	From    string                 `json:"from"`
+	To      string                 `json:"to"`
+	Reports []*PropertyMonthReport `json:"reports"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+type Review struct {
+	ID         int64      `json:"id"`
+	BookingID  int64      `json:"booking_id"`
+	PropertyID int64      `json:"property_id"`
+	GuestID    int64      `json:"guest_id"`
+	Rating     int32      `json:"rating"`
+	Text       string     `json:"text"`
+	Reply      *string    `json:"reply"`
+	RepliedAt  *time.Time `json:"replied_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateReviewRequest struct {
+	BookingID int64  `json:"booking_id" binding:"required"`
+	Rating    int32  `json:"rating" binding:"required,min=1,max=5"`
+	Text      string `json:"text" binding:"required,max=2000"`
+}
+
+type ReplyReviewRequest struct {
+	Reply string `json:"reply" binding:"required,max=2000"`
+}
+
+type GetReviewsResponse struct {
+	Reviews []*Review `json:"reviews"`
+	Count   int32     `json:"count"`
+}
+
+type PropertyRatingInfo struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+	// Histogram maps each star rating to the number of reviews with it
+	Histogram map[int32]int64 `json:"histogram"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type Room struct {
+	ID          int64      `json:"id"`
+	PropertyID  int64      `json:"property_id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Capacity    int32      `json:"capacity"`
+	BasePrice   float64    `json:"base_price"`
+	Photos      []string   `json:"photos"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+type CreateRoomRequest struct {
+	Title       string   `json:"title" binding:"required,max=100"`
+	Description string   `json:"description"`
+	Capacity    int32    `json:"capacity" binding:"required,min=1"`
+	BasePrice   float64  `json:"base_price" binding:"required,gt=0"`
+	Photos      []string `json:"photos" binding:"dive,startswith=/media/"`
+}
+
+type GetRoomsResponse struct {
+	Rooms []*Room `json:"rooms"`
+	Count int32   `json:"count"`
+}
+
+type RoomBlock struct {
+	ID        int64      `json:"id"`
+	RoomID    int64      `json:"room_id"`
+	StartDate string     `json:"start_date"`
+	EndDate   string     `json:"end_date"`
+	Reason    string     `json:"reason"`
+	Source    string     `json:"source"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+type CreateRoomBlockRequest struct {
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+	Reason    string `json:"reason" binding:"max=500"`
+}
+
+type GetRoomBlocksResponse struct {
+	Blocks []*RoomBlock `json:"blocks"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+type TwoFactorEnrollResponse struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+type TwoFactorVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required,max=32"`
+}
+
+// MFAChallengeResponse is returned by a login which needs a second step.
+// Type is "verify" if a code must be sent to /auth/2fa/verify along with the
+// token, or "enroll" if two-factor authentication must first be set up using
+// the token as the access token.
+type MFAChallengeResponse struct {
+	Type      string    `json:"type"`
+	MFAToken  string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type TwoFactorStatus struct {
+	Enabled  bool `json:"enabled"`
+	Required bool `json:"required"`
+}
+
+type TwoFactorPolicy struct {
+	Required bool `json:"required"`
+}
+
+type UpdateTwoFactorPolicyRequest struct {
+	Required *bool `json:"required" binding:"required"`
+}
@@ -5,17 +5,18 @@ import (
 )
 
 type User struct {
-	ID              int64     `json:"id"`
-	FirstName       string    `json:"first_name"`
-	LastName        string    `json:"last_name"`
-	DateOfBirth     string    `json:"dob"`
-	Email           string    `json:"email"`
-	PhoneNumber     *string   `json:"phone_number"`
-	Gender          string    `json:"gender"`
-	ProfileImageUrl *string   `json:"profile_image_url"`
-	Address         *string   `json:"address"`
-	Type            string    `json:"type"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              int64      `json:"id"`
+	FirstName       string     `json:"first_name"`
+	LastName        string     `json:"last_name"`
+	DateOfBirth     string     `json:"dob"`
+	Email           string     `json:"email"`
+	PhoneNumber     *string    `json:"phone_number"`
+	Gender          string     `json:"gender"`
+	ProfileImageUrl *string    `json:"profile_image_url"`
+	Address         *string    `json:"address"`
+	Type            string     `json:"type"`
+	CreatedAt       time.Time  `json:"created_at"`
+	BannedAt        *time.Time `json:"banned_at"`
 }
 
 type CreateUserRequest struct {
@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /api-keys [post]
+// @Summary Create an API key
+// @Description Create an API key acting as the current user, limited to the scopes, which must be permissions of the user. The key is only returned once. Send it as "Authorization: ApiKey <key>". Keys are deleted when the password of the user changes, and are refused while two-factor authentication is required of the user but not enabled.
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Param data body models.CreateAPIKeyRequest true "Data"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateAPIKey(ctx *gin.Context) {
+
+	var req models.CreateAPIKeyRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	for _, scope := range req.Scopes {
+		p := policy.Permission(scope)
+		if !policy.Known(p) || !policy.Can(authSubject(payload), p) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("%w: %s", ErrInvalidScope, scope)))
+			return
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrExpiryInPast))
+		return
+	}
+
+	key, prefix, hash, err := utils.CreateAPIKey()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	apiKey, err := h.storage.APIKey().Create(ctx.Request.Context(), &repo.APIKey{
+		UserID:    payload.UserID,
+		Name:      req.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.CreateAPIKeyResponse{
+		APIKey: parseAPIKeyToModel(apiKey),
+		Key:    key,
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /api-keys [get]
+// @Summary Get API keys
+// @Description Get the API keys of the current user
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.GetAPIKeysResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetAPIKeys(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	keys, err := h.storage.APIKey().GetAll(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := models.GetAPIKeysResponse{
+		APIKeys: make([]*models.APIKey, 0),
+	}
+
+	for _, key := range keys {
+		k := parseAPIKeyToModel(key)
+		response.APIKeys = append(response.APIKeys, &k)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Security ApiKeyAuth
+// @Router /api-keys/{id} [delete]
+// @Summary Delete an API key
+// @Description Delete an API key of the current user, which can no longer be used
+// @Tags api-key
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeleteAPIKey(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.storage.APIKey().Delete(ctx.Request.Context(), id, payload.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
+
+func parseAPIKeyToModel(key *repo.APIKey) models.APIKey {
+	return models.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     utils.APIKeyPrefix + key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
@@ -18,6 +19,8 @@ import (
 const (
 	RegisterCodeKey   = "register_code_"
 	ForgotPasswordKey = "forgot_password_code_"
+
+	verificationCodeTTL = time.Minute
 )
 
 // @Router /auth/register [post]
@@ -40,7 +43,7 @@ func (h *handlerV1) Register(ctx *gin.Context) {
 		return
 	}
 
-	_, err = h.storage.User().GetByEmail(req.Email)
+	_, err = h.storage.User().GetByEmail(ctx.Request.Context(), req.Email)
 	if !errors.Is(err, sql.ErrNoRows) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(ErrEmailExists))
 		return
@@ -67,14 +70,15 @@ func (h *handlerV1) Register(ctx *gin.Context) {
 		return
 	}
 
-	err = h.inMemory.Set("user_"+user.Email, string(userData), 10*time.Minute)
+	err = h.inMemory.Set(ctx.Request.Context(), "user_"+user.Email, string(userData), 10*time.Minute)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
+	// The request context is cancelled once the response is written
 	go func() {
-		err := h.sendVerificationCode(RegisterCodeKey, req.Email)
+		err := h.sendVerificationCode(context.Background(), RegisterCodeKey, req.Email)
 		if err != nil {
 			fmt.Printf("failed to send verification code: %v", err)
 		}
@@ -85,13 +89,19 @@ func (h *handlerV1) Register(ctx *gin.Context) {
 	})
 }
 
-func (h *handlerV1) sendVerificationCode(key, email string) error {
+func (h *handlerV1) sendVerificationCode(ctx context.Context, key, email string) error {
 	code, err := utils.GenerateRandomCode(6)
 	if err != nil {
 		return err
 	}
 
-	err = h.inMemory.Set(key+email, code, time.Minute)
+	err = h.inMemory.Set(ctx, key+email, code, verificationCodeTTL)
+	if err != nil {
+		return err
+	}
+
+	// A new code gets a fresh count of tries
+	err = h.inMemory.Delete(ctx, codeAttemptsKey+key+email)
 	if err != nil {
 		return err
 	}
@@ -119,9 +129,11 @@ func (h *handlerV1) sendVerificationCode(key, email string) error {
 // @Produce json
 // @Param data body models.VerifyRequest true "Data"
 // @Success 201 {object} models.AuthResponse
+// @Success 202 {object} models.MFAChallengeResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) Verfiy(ctx *gin.Context) {
 
@@ -133,7 +145,11 @@ func (h *handlerV1) Verfiy(ctx *gin.Context) {
 		return
 	}
 
-	userData, err := h.inMemory.Get("user_" + req.Email)
+	if !h.checkLockout(ctx, h.limiters.email, req.Email) {
+		return
+	}
+
+	userData, err := h.inMemory.Get(ctx.Request.Context(), "user_"+req.Email)
 	if err != nil {
 		ctx.JSON(http.StatusForbidden, errorResponse(err))
 		return
@@ -146,56 +162,48 @@ func (h *handlerV1) Verfiy(ctx *gin.Context) {
 		return
 	}
 
-	code, err := h.inMemory.Get(RegisterCodeKey + user.Email)
+	code, err := h.inMemory.Get(ctx.Request.Context(), RegisterCodeKey+user.Email)
 	if err != nil {
 		ctx.JSON(http.StatusForbidden, errorResponse(ErrCodeExpired))
 		return
 	}
 
 	if req.Code != code {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrIncorrectCode))
+		h.wrongCode(ctx, RegisterCodeKey+user.Email, req.Email)
 		return
 	}
 
-	result, err := h.storage.User().Create(&user)
+	err = h.inMemory.Delete(ctx.Request.Context(), RegisterCodeKey+user.Email)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	token, _, err := utils.CreateToken(h.cfg, &utils.TokenParams{
-		UserID:   result.ID,
-		UserType: result.Type,
-		Email:    result.Email,
-		Duration: time.Hour * 24,
-	})
+	// The code was sent to the email, so the user owns it
+	verifiedAt := time.Now()
+	user.EmailVerifiedAt = &verifiedAt
+
+	result, err := h.storage.User().Create(ctx.Request.Context(), &user)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, models.AuthResponse{
-		ID:          result.ID,
-		FirstName:   result.FirstName,
-		LastName:    result.LastName,
-		DateOfBirth: result.DateOfBirth,
-		Email:       result.Email,
-		Type:        result.Type,
-		CreatedAt:   result.CreatedAt,
-		AccessToken: token,
-	})
+	h.completeLogin(ctx, result, http.StatusCreated)
 }
 
 // @Router /auth/login [post]
 // @Summary Login user
-// @Description Login user
+// @Description Login user. If two-factor authentication is enabled for, or required of, the user, a challenge is returned with status 202 instead of the tokens.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param data body models.LoginRequest true "Data"
 // @Success 201 {object} models.AuthResponse
+// @Success 202 {object} models.MFAChallengeResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) Login(ctx *gin.Context) {
 
@@ -207,10 +215,14 @@ func (h *handlerV1) Login(ctx *gin.Context) {
 		return
 	}
 
-	result, err := h.storage.User().GetByEmail(req.Email)
+	if !h.checkLockout(ctx, h.limiters.email, req.Email) {
+		return
+	}
+
+	result, err := h.storage.User().GetByEmail(ctx.Request.Context(), req.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			ctx.JSON(http.StatusForbidden, errorResponse(ErrWrongEmailOrPass))
+			h.failAttempt(ctx, h.limiters.email, req.Email, http.StatusForbidden, ErrWrongEmailOrPass)
 			return
 		}
 
@@ -220,31 +232,22 @@ func (h *handlerV1) Login(ctx *gin.Context) {
 
 	err = utils.CheckPassword(req.Password, result.Password)
 	if err != nil {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrWrongEmailOrPass))
+		h.failAttempt(ctx, h.limiters.email, req.Email, http.StatusForbidden, ErrWrongEmailOrPass)
 		return
 	}
 
-	token, _, err := utils.CreateToken(h.cfg, &utils.TokenParams{
-		UserID:   result.ID,
-		UserType: result.Type,
-		Email:    result.Email,
-		Duration: time.Hour * 24,
-	})
+	err = h.limiters.email.Reset(ctx.Request.Context(), req.Email)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	ctx.JSON(http.StatusCreated, models.AuthResponse{
-		ID:          result.ID,
-		FirstName:   result.FirstName,
-		LastName:    result.LastName,
-		DateOfBirth: result.DateOfBirth,
-		Email:       result.Email,
-		Type:        result.Type,
-		CreatedAt:   result.CreatedAt,
-		AccessToken: token,
-	})
+	if result.BannedAt != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrUserBanned))
+		return
+	}
+
+	h.completeLogin(ctx, result, http.StatusCreated)
 }
 
 // @Router /auth/forgot-password [post]
@@ -268,7 +271,7 @@ func (h *handlerV1) ForgotPassword(ctx *gin.Context) {
 		return
 	}
 
-	_, err = h.storage.User().GetByEmail(req.Email)
+	_, err = h.storage.User().GetByEmail(ctx.Request.Context(), req.Email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(ErrEmailExists))
@@ -279,7 +282,7 @@ func (h *handlerV1) ForgotPassword(ctx *gin.Context) {
 	}
 
 	go func() {
-		err := h.sendVerificationCode(ForgotPasswordKey, req.Email)
+		err := h.sendVerificationCode(context.Background(), ForgotPasswordKey, req.Email)
 		if err != nil {
 			fmt.Printf("failed to send verification code: %v", err)
 		}
@@ -292,7 +295,7 @@ func (h *handlerV1) ForgotPassword(ctx *gin.Context) {
 
 // @Router /auth/verify-forgot-password [post]
 // @Summary Verify forgot password
-// @Description Verify forgot password
+// @Description Verify forgot password. The returned access token can only be used to update the password.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -300,6 +303,7 @@ func (h *handlerV1) ForgotPassword(ctx *gin.Context) {
 // @Success 201 {object} models.AuthResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) VerfiyForgotPassword(ctx *gin.Context) {
 
@@ -311,28 +315,39 @@ func (h *handlerV1) VerfiyForgotPassword(ctx *gin.Context) {
 		return
 	}
 
-	code, err := h.inMemory.Get(ForgotPasswordKey + req.Email)
+	if !h.checkLockout(ctx, h.limiters.email, req.Email) {
+		return
+	}
+
+	code, err := h.inMemory.Get(ctx.Request.Context(), ForgotPasswordKey+req.Email)
 	if err != nil {
 		ctx.JSON(http.StatusForbidden, errorResponse(ErrCodeExpired))
 		return
 	}
 
 	if req.Code != code {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrIncorrectCode))
+		h.wrongCode(ctx, ForgotPasswordKey+req.Email, req.Email)
+		return
+	}
+
+	err = h.inMemory.Delete(ctx.Request.Context(), ForgotPasswordKey+req.Email)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	result, err := h.storage.User().GetByEmail(req.Email)
+	result, err := h.storage.User().GetByEmail(ctx.Request.Context(), req.Email)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	token, _, err := utils.CreateToken(h.cfg, &utils.TokenParams{
+	token, _, err := utils.CreateToken(h.keys, &utils.TokenParams{
 		UserID:   result.ID,
 		UserType: result.Type,
 		Email:    result.Email,
-		Duration: time.Minute * 30,
+		Scope:    utils.ScopePasswordReset,
+		Duration: resetPasswordTokenTTL,
 	})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -354,7 +369,7 @@ func (h *handlerV1) VerfiyForgotPassword(ctx *gin.Context) {
 // @Security ApiKeyAuth
 // @Router /auth/update-password [post]
 // @Summary Update password
-// @Description Update password
+// @Description Update password. Every token, session and API key of the user is revoked, including the current token.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -384,7 +399,7 @@ func (h *handlerV1) UpdatePassword(ctx *gin.Context) {
 		return
 	}
 
-	err = h.storage.User().UpdatePassword(&repo.UpdatePassword{
+	err = h.storage.User().UpdatePassword(ctx.Request.Context(), &repo.UpdatePassword{
 		UserID:   payload.UserID,
 		Password: hashedPassword,
 	})
@@ -393,6 +408,12 @@ func (h *handlerV1) UpdatePassword(ctx *gin.Context) {
 		return
 	}
 
+	err = h.revokeUserTokens(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
 	ctx.JSON(http.StatusOK, models.OKResponse{
 		Message: "Password has been updated",
 	})
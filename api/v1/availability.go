@@ -0,0 +1,241 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/pricing"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const monthLayout = "2006-01"
+
+func validateGetAvailabilityParams(ctx *gin.Context) (*repo.GetAvailablePropertiesParams, error) {
+	var (
+		limit  int64 = 10
+		page   int64 = 1
+		guests int64 = 1
+		err    error
+	)
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.ParseInt(ctx.Query("limit"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("page") != "" {
+		page, err = strconv.ParseInt(ctx.Query("page"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("guests") != "" {
+		guests, err = strconv.ParseInt(ctx.Query("guests"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	checkIn, checkOut, err := parseStayDates(ctx.Query("check_in"), ctx.Query("check_out"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &repo.GetAvailablePropertiesParams{
+		Limit:    int32(limit),
+		Page:     int32(page),
+		City:     ctx.Query("city"),
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Guests:   int32(guests),
+	}, nil
+}
+
+// @Router /availability [get]
+// @Summary Search available properties
+// @Description Get properties which have at least one room free for the whole stay
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param filter query models.GetAvailabilityParams false "Filter"
+// @Success 200 {object} models.GetAvailabilityResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetAvailability(ctx *gin.Context) {
+	params, err := validateGetAvailabilityParams(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := h.storage.Property().GetAvailable(ctx.Request.Context(), params)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response, err := getAvailabilityResponse(ctx.Request.Context(), h, params, result)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// getAvailabilityResponse loads the free rooms of all the properties and their
+// pricing rules in one query each, and quotes the stays in memory
+func getAvailabilityResponse(ctx context.Context, h *handlerV1, params *repo.GetAvailablePropertiesParams,
+	data *repo.GetPropertiesResult) (*models.GetAvailabilityResponse, error) {
+	response := models.GetAvailabilityResponse{
+		Properties: make([]*models.AvailableProperty, 0),
+		Count:      data.Count,
+	}
+
+	if len(data.Properties) == 0 {
+		return &response, nil
+	}
+
+	propertyIDs := make([]int64, 0, len(data.Properties))
+	for _, property := range data.Properties {
+		propertyIDs = append(propertyIDs, property.ID)
+	}
+
+	rooms, err := h.storage.Room().GetAvailable(ctx, &repo.GetAvailableRoomsParams{
+		PropertyIDs: propertyIDs,
+		CheckIn:     params.CheckIn,
+		CheckOut:    params.CheckOut,
+		Guests:      params.Guests,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	roomIDs := make([]int64, 0, len(rooms))
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+
+	pricingRules, err := h.storage.PricingRule().GetByRooms(ctx, roomIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	roomRules := make(map[int64][]*repo.PricingRule)
+	for _, rule := range pricingRules {
+		roomRules[rule.RoomID] = append(roomRules[rule.RoomID], rule)
+	}
+
+	propertyRooms := make(map[int64][]*models.AvailableRoom)
+
+	for _, room := range rooms {
+		quote, err := pricing.Calculate(room.BasePrice, parsePricingRules(roomRules[room.ID]), params.CheckIn, params.CheckOut)
+		if err != nil {
+			return nil, err
+		}
+
+		q := parseQuoteToModel(quote)
+
+		propertyRooms[room.PropertyID] = append(propertyRooms[room.PropertyID], &models.AvailableRoom{
+			Room:  parseRoomToModel(room),
+			Quote: &q,
+		})
+	}
+
+	for _, property := range data.Properties {
+		p := models.AvailableProperty{
+			Property:       parsePropertyToModel(property),
+			AvailableRooms: make([]*models.AvailableRoom, 0),
+		}
+
+		p.AvailableRooms = append(p.AvailableRooms, propertyRooms[property.ID]...)
+
+		response.Properties = append(response.Properties, &p)
+	}
+
+	return &response, nil
+}
+
+// @Router /rooms/{id}/calendar [get]
+// @Summary Get calendar of a room
+// @Description Get per-day availability and price of a room for a month
+// @Tags availability
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Param month query string false "Month" example(2006-01)
+// @Success 200 {object} models.RoomCalendarResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetRoomCalendar(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	month := time.Now().Format(monthLayout)
+	if ctx.Query("month") != "" {
+		month = ctx.Query("month")
+	}
+
+	from, err := time.Parse(monthLayout, month)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	room, err := h.storage.Room().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	days, err := h.storage.Room().GetCalendar(ctx.Request.Context(), &repo.GetRoomCalendarParams{
+		RoomID: id,
+		From:   from,
+		To:     from.AddDate(0, 1, 0),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	pricingRules, err := h.storage.PricingRule().GetAll(ctx.Request.Context(), room.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rules := parsePricingRules(pricingRules)
+
+	response := models.RoomCalendarResponse{
+		RoomID: id,
+		Month:  month,
+		Days:   make([]*models.RoomCalendarDay, 0),
+	}
+
+	for _, day := range days {
+		response.Days = append(response.Days, &models.RoomCalendarDay{
+			Date:      day.Date.Format(dateLayout),
+			Available: day.Available,
+			Price:     pricing.NightlyPrice(day.Price, rules, day.Date),
+		})
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,400 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
+	"github.com/ibrat-muslim/booking-service/pkg/pricing"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const dateLayout = "2006-01-02"
+
+// @Security ApiKeyAuth
+// @Router /bookings [post]
+// @Summary Create a booking
+// @Description Create a booking
+// @Tags booking
+// @Accept json
+// @Produce json
+// @Param booking body models.CreateBookingRequest true "Booking"
+// @Success 201 {object} models.Booking
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateBooking(ctx *gin.Context) {
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var req models.CreateBookingRequest
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	checkIn, checkOut, err := parseStayDates(req.CheckIn, req.CheckOut)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	room, err := h.storage.Room().Get(ctx.Request.Context(), req.RoomID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.GuestsCount > room.Capacity {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrCapacityExceeded))
+		return
+	}
+
+	quote, err := h.quoteStay(ctx.Request.Context(), room, checkIn, checkOut)
+	if err != nil {
+		var minNightsErr *pricing.MinNightsError
+		if errors.As(err, &minNightsErr) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp, err := h.storage.Booking().Create(ctx.Request.Context(), &repo.Booking{
+		RoomID:      room.ID,
+		GuestID:     payload.UserID,
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		GuestsCount: req.GuestsCount,
+		TotalPrice:  quote.Total,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrRoomNotAvailable) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, parseBookingToModel(resp))
+}
+
+// parseStayDates parses check-in and check-out dates and makes sure they form
+// a stay of at least one night which does not start in the past
+func parseStayDates(checkInStr, checkOutStr string) (time.Time, time.Time, error) {
+	checkIn, err := time.Parse(dateLayout, checkInStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	checkOut, err := time.Parse(dateLayout, checkOutStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !checkOut.After(checkIn) {
+		return time.Time{}, time.Time{}, ErrInvalidDateRange
+	}
+
+	today, _ := time.Parse(dateLayout, time.Now().Format(dateLayout))
+	if checkIn.Before(today) {
+		return time.Time{}, time.Time{}, ErrCheckInInPast
+	}
+
+	return checkIn, checkOut, nil
+}
+
+// @Security ApiKeyAuth
+// @Router /bookings/{id} [get]
+// @Summary Get a booking by id
+// @Description Get a booking by id
+// @Tags booking
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.Booking
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetBooking(ctx *gin.Context) {
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	booking, _, ok := h.getAccessibleBooking(ctx, id)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parseBookingToModel(booking))
+}
+
+func validateGetBookingsParams(ctx *gin.Context) (*models.GetBookingsParams, error) {
+	var (
+		limit      int64 = 10
+		page       int64 = 1
+		roomID     int64
+		propertyID int64
+		err        error
+	)
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.ParseInt(ctx.Query("limit"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("page") != "" {
+		page, err = strconv.ParseInt(ctx.Query("page"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("room_id") != "" {
+		roomID, err = strconv.ParseInt(ctx.Query("room_id"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("property_id") != "" {
+		propertyID, err = strconv.ParseInt(ctx.Query("property_id"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.GetBookingsParams{
+		Limit:      int32(limit),
+		Page:       int32(page),
+		RoomID:     roomID,
+		PropertyID: propertyID,
+		Status:     ctx.Query("status"),
+	}, nil
+}
+
+// @Security ApiKeyAuth
+// @Router /bookings [get]
+// @Summary Get bookings
+// @Description Guests get their own bookings, owners get bookings of their properties
+// @Tags booking
+// @Accept json
+// @Produce json
+// @Param filter query models.GetBookingsParams false "Filter"
+// @Success 200 {object} models.GetBookingsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetBookings(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	request, err := validateGetBookingsParams(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	params := repo.GetBookingsParams{
+		Limit:      request.Limit,
+		Page:       request.Page,
+		RoomID:     request.RoomID,
+		PropertyID: request.PropertyID,
+		Status:     request.Status,
+	}
+
+	switch payload.UserType {
+	case repo.UserTypeGuest:
+		params.GuestID = payload.UserID
+	case repo.UserTypeOwner:
+		params.OwnerID = payload.UserID
+	}
+
+	result, err := h.storage.Booking().GetAll(ctx.Request.Context(), &params)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getBookingsResponse(result))
+}
+
+func getBookingsResponse(data *repo.GetBookingsResult) *models.GetBookingsResponse {
+	response := models.GetBookingsResponse{
+		Bookings: make([]*models.Booking, 0),
+		Count:    data.Count,
+	}
+
+	for _, booking := range data.Bookings {
+		b := parseBookingToModel(booking)
+		response.Bookings = append(response.Bookings, &b)
+	}
+
+	return &response
+}
+
+// @Security ApiKeyAuth
+// @Router /bookings/{id}/status [put]
+// @Summary Update status of a booking
+// @Description Owners may move bookings of their properties through any valid transition. Bookings are confirmed by paying via /bookings/{id}/pay and cancelled via /bookings/{id}/cancel.
+// @Tags booking
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Param status body models.UpdateBookingStatusRequest true "Status"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) UpdateBookingStatus(ctx *gin.Context) {
+	var req models.UpdateBookingStatusRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, canManage, ok := h.getAccessibleBooking(ctx, id)
+	if !ok {
+		return
+	}
+
+	if !canManage {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return
+	}
+
+	err = h.storage.Booking().UpdateStatus(ctx.Request.Context(), &repo.UpdateBookingStatus{
+		ID:     id,
+		Status: req.Status,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, repo.ErrInvalidStatusTransition) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully updated",
+	})
+}
+
+// getAccessibleBooking loads the booking and checks that the caller is either
+// its guest or may manage the property it belongs to. The second return value
+// reports the latter. On failure the response is already written.
+func (h *handlerV1) getAccessibleBooking(ctx *gin.Context, id int64) (*repo.Booking, bool, bool) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false, false
+	}
+
+	booking, err := h.storage.Booking().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return nil, false, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false, false
+	}
+
+	canManage, err := h.canManageBooking(ctx.Request.Context(), payload, booking)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false, false
+	}
+
+	if !canManage && booking.GuestID != payload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return nil, false, false
+	}
+
+	return booking, canManage, true
+}
+
+func (h *handlerV1) canManageBooking(ctx context.Context, payload *utils.Payload, booking *repo.Booking) (bool, error) {
+	switch policy.GrantOf(authSubject(payload), policy.BookingManage) {
+	case policy.Any:
+		return true, nil
+	case policy.None:
+		return false, nil
+	}
+
+	room, err := h.storage.Room().Get(ctx, booking.RoomID)
+	if err != nil {
+		return false, err
+	}
+
+	property, err := h.storage.Property().Get(ctx, room.PropertyID)
+	if err != nil {
+		return false, err
+	}
+
+	return policy.CanOn(authSubject(payload), policy.BookingManage, property.OwnerID), nil
+}
+
+func parseBookingToModel(booking *repo.Booking) models.Booking {
+	return models.Booking{
+		ID:           booking.ID,
+		RoomID:       booking.RoomID,
+		GuestID:      booking.GuestID,
+		CheckIn:      booking.CheckIn.Format(dateLayout),
+		CheckOut:     booking.CheckOut.Format(dateLayout),
+		GuestsCount:  booking.GuestsCount,
+		TotalPrice:   booking.TotalPrice,
+		Status:       booking.Status,
+		RefundAmount: booking.RefundAmount,
+		CancelledAt:  booking.CancelledAt,
+		CreatedAt:    booking.CreatedAt,
+		UpdatedAt:    booking.UpdatedAt,
+	}
+}
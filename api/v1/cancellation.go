@@ -0,0 +1,184 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/cancellation"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /bookings/{id}/cancel [post]
+// @Summary Cancel a booking
+// @Description Guests get a refund according to the cancellation policy of the property, bookings cancelled by the owner are refunded in full. The refund is paid back to the captured payment of the booking, unpaid bookings are not refunded. Cancelling a cancelled booking again retries its refund.
+// @Tags booking
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.Booking
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CancelBooking(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	booking, canManage, ok := h.getAccessibleBooking(ctx, id)
+	if !ok {
+		return
+	}
+
+	// A cancelled booking is only refunded again, so that a refund which
+	// failed at the provider can be retried
+	if booking.Status != repo.BookingStatusCancelled {
+		cancelledAt := time.Now()
+		refundAmount := booking.TotalPrice
+
+		if !canManage {
+			refundAmount, err = h.refundAmount(ctx.Request.Context(), booking, cancelledAt)
+			if err != nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+		}
+
+		err = h.storage.Booking().Cancel(ctx.Request.Context(), &repo.CancelBooking{
+			ID:           id,
+			RefundAmount: refundAmount,
+			CancelledAt:  cancelledAt,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				ctx.JSON(http.StatusNotFound, errorResponse(err))
+				return
+			}
+			if errors.Is(err, repo.ErrInvalidStatusTransition) {
+				ctx.JSON(http.StatusBadRequest, errorResponse(err))
+				return
+			}
+			if errors.Is(err, repo.ErrPaymentInProgress) {
+				ctx.JSON(http.StatusConflict, errorResponse(err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		booking, err = h.storage.Booking().Get(ctx.Request.Context(), id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	err = h.refundPayment(ctx.Request.Context(), booking)
+	if err != nil {
+		if errors.Is(err, ErrRefundInProgress) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parseBookingToModel(booking))
+}
+
+// refundAmount computes the refund of a booking cancelled by its guest at
+// cancelledAt from the cancellation policy of the booked property
+func (h *handlerV1) refundAmount(ctx context.Context, booking *repo.Booking, cancelledAt time.Time) (float64, error) {
+	room, err := h.storage.Room().Get(ctx, booking.RoomID)
+	if err != nil {
+		return 0, err
+	}
+
+	property, err := h.storage.Property().Get(ctx, room.PropertyID)
+	if err != nil {
+		return 0, err
+	}
+
+	tiers, err := cancellationTiers(property)
+	if err != nil {
+		return 0, err
+	}
+
+	_, amount := cancellation.Refund(tiers, booking.TotalPrice, booking.CheckIn, cancelledAt)
+
+	return amount, nil
+}
+
+// parseCancellationPolicyRequest returns the policy of the request, moderate by
+// default, and its tiers. Tiers are only kept for the custom policy.
+func parseCancellationPolicyRequest(req *models.CreatePropertyRequest) (string, []*repo.CancellationTier, error) {
+	policy := req.CancellationPolicy
+	if policy == "" {
+		policy = repo.CancellationPolicyModerate
+	}
+
+	if policy != repo.CancellationPolicyCustom {
+		return policy, nil, nil
+	}
+
+	if len(req.CancellationTiers) == 0 {
+		return "", nil, cancellation.ErrNoTiers
+	}
+
+	tiers := make([]*repo.CancellationTier, 0, len(req.CancellationTiers))
+
+	for _, tier := range req.CancellationTiers {
+		tiers = append(tiers, &repo.CancellationTier{
+			DaysBefore:    tier.DaysBefore,
+			RefundPercent: tier.RefundPercent,
+		})
+	}
+
+	return policy, tiers, nil
+}
+
+func cancellationTiers(property *repo.Property) ([]cancellation.Tier, error) {
+	custom := make([]cancellation.Tier, 0, len(property.CancellationTiers))
+
+	for _, tier := range property.CancellationTiers {
+		custom = append(custom, cancellation.Tier{
+			DaysBefore:    int(tier.DaysBefore),
+			RefundPercent: tier.RefundPercent,
+		})
+	}
+
+	return cancellation.Tiers(property.CancellationPolicy, custom)
+}
+
+func parseCancellationPolicyToModel(property *repo.Property) *models.CancellationPolicy {
+	result := models.CancellationPolicy{
+		Type:  property.CancellationPolicy,
+		Tiers: make([]*models.CancellationTier, 0),
+	}
+
+	tiers, err := cancellationTiers(property)
+	if err != nil {
+		return &result
+	}
+
+	for _, tier := range tiers {
+		result.Tiers = append(result.Tiers, &models.CancellationTier{
+			DaysBefore:    int32(tier.DaysBefore),
+			RefundPercent: tier.RefundPercent,
+		})
+	}
+
+	result.Description = cancellation.Describe(tiers)
+
+	return &result
+}
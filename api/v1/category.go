@@ -24,27 +24,15 @@ import (
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) CreateCategory(ctx *gin.Context) {
-
-	payload, err := h.GetAuthPayload(ctx)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if payload.UserType != repo.UserTypeSuperAdmin {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
-		return
-	}
-
 	var req models.CreateCategoryRequest
 
-	err = ctx.ShouldBindJSON(&req)
+	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	resp, err := h.storage.Category().Create(&repo.Category{Title: req.Title})
+	resp, err := h.storage.Category().Create(ctx.Request.Context(), &repo.Category{Title: req.Title})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -76,7 +64,7 @@ func (h *handlerV1) GetCategory(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.Category().Get(id)
+	resp, err := h.storage.Category().Get(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -110,7 +98,7 @@ func (h *handlerV1) GetCategories(ctx *gin.Context) {
 		return
 	}
 
-	result, err := h.storage.Category().GetAll(&repo.GetCategoriesParams{
+	result, err := h.storage.Category().GetAll(ctx.Request.Context(), &repo.GetCategoriesParams{
 		Limit:  request.Limit,
 		Page:   request.Page,
 		Search: request.Search,
@@ -155,21 +143,9 @@ func getCategoriesResponse(data *repo.GetCategoriesResult) *models.GetCategories
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) UpdateCategory(ctx *gin.Context) {
-
-	payload, err := h.GetAuthPayload(ctx)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if payload.UserType != repo.UserTypeSuperAdmin {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
-		return
-	}
-
 	var req models.CreateCategoryRequest
 
-	err = ctx.ShouldBindJSON(&req)
+	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
@@ -181,7 +157,7 @@ func (h *handlerV1) UpdateCategory(ctx *gin.Context) {
 		return
 	}
 
-	err = h.storage.Category().Update(&repo.Category{
+	err = h.storage.Category().Update(ctx.Request.Context(), &repo.Category{
 		ID:    id,
 		Title: req.Title,
 	})
@@ -213,25 +189,13 @@ func (h *handlerV1) UpdateCategory(ctx *gin.Context) {
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) DeleteCategory(ctx *gin.Context) {
-
-	payload, err := h.GetAuthPayload(ctx)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if payload.UserType != repo.UserTypeSuperAdmin {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
-		return
-	}
-
 	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
 
-	err = h.storage.Category().Delete(id)
+	err = h.storage.Category().Delete(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
 	"github.com/ibrat-muslim/booking-service/storage/repo"
 )
 
@@ -39,7 +40,7 @@ func (h *handlerV1) CreateComment(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.Comment().Create(&repo.Comment{
+	resp, err := h.storage.Comment().Create(ctx.Request.Context(), &repo.Comment{
 		PostID:      req.PostID,
 		UserID:      payload.UserID,
 		Description: req.Description,
@@ -113,7 +114,7 @@ func (h *handlerV1) GetComments(ctx *gin.Context) {
 		return
 	}
 
-	result, err := h.storage.Comment().GetAll(&repo.GetCommentsParams{
+	result, err := h.storage.Comment().GetAll(ctx.Request.Context(), &repo.GetCommentsParams{
 		Limit:  request.Limit,
 		Page:   request.Page,
 		PostID: request.PostID,
@@ -161,6 +162,7 @@ func getCommentsResponse(data *repo.GetCommentsResult) *models.GetCommentsRespon
 // @Param comment body models.CreateCommentRequest true "Comment"
 // @Success 200 {object} models.OKResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) UpdateComment(ctx *gin.Context) {
@@ -178,9 +180,23 @@ func (h *handlerV1) UpdateComment(ctx *gin.Context) {
 		return
 	}
 
+	authorID, err := h.storage.Comment().GetAuthorID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !h.authorize(ctx, policy.CommentUpdate, authorID) {
+		return
+	}
+
 	updatedAt := time.Now()
 
-	err = h.storage.Comment().Update(&repo.Comment{
+	err = h.storage.Comment().Update(ctx.Request.Context(), &repo.Comment{
 		ID:          id,
 		Description: req.Description,
 		UpdatedAt:   &updatedAt,
@@ -209,6 +225,7 @@ func (h *handlerV1) UpdateComment(ctx *gin.Context) {
 // @Param id path int true "ID"
 // @Success 200 {object} models.OKResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) DeleteComment(ctx *gin.Context) {
@@ -218,7 +235,21 @@ func (h *handlerV1) DeleteComment(ctx *gin.Context) {
 		return
 	}
 
-	err = h.storage.Comment().Delete(id)
+	authorID, err := h.storage.Comment().GetAuthorID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !h.authorize(ctx, policy.CommentDelete, authorID) {
+		return
+	}
+
+	err = h.storage.Comment().Delete(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -7,28 +7,65 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ibrat-muslim/booking-service/api/models"
 	"github.com/ibrat-muslim/booking-service/config"
+	"github.com/ibrat-muslim/booking-service/pkg/keyring"
+	"github.com/ibrat-muslim/booking-service/pkg/oidc"
+	"github.com/ibrat-muslim/booking-service/pkg/payments"
 	"github.com/ibrat-muslim/booking-service/storage"
 )
 
 var (
-	ErrWrongEmailOrPass = errors.New("wrong email or password")
-	ErrUserNotVerified  = errors.New("user not verified")
-	ErrEmailExists      = errors.New("email already exists")
-	ErrIncorrectCode    = errors.New("incorrect verification code")
-	ErrCodeExpired      = errors.New("verification code has been expired")
-	ErrForbidden        = errors.New("forbidden")
+	ErrWrongEmailOrPass     = errors.New("wrong email or password")
+	ErrUserNotVerified      = errors.New("user not verified")
+	ErrEmailExists          = errors.New("email already exists")
+	ErrIncorrectCode        = errors.New("incorrect verification code")
+	ErrCodeExpired          = errors.New("verification code has been expired")
+	ErrForbidden            = errors.New("forbidden")
+	ErrInvalidDateRange     = errors.New("check_out must be after check_in")
+	ErrCheckInInPast        = errors.New("check_in must not be in the past")
+	ErrCapacityExceeded     = errors.New("guests count exceeds room capacity")
+	ErrInvalidPricingRule   = errors.New("pricing rule is missing fields required by its type")
+	ErrInvalidRuleDates     = errors.New("start_date and end_date must be set together and ordered")
+	ErrRefundInProgress     = errors.New("a refund of the booking is already in progress")
+	ErrStayNotCompleted     = errors.New("only completed stays can be reviewed")
+	ErrInvalidReportRange   = errors.New("to must not be before from nor 24 months or more after it")
+	ErrInvalidBlockDates    = errors.New("end_date must be after start_date")
+	ErrSessionRevoked       = errors.New("session has been revoked or expired")
+	ErrRefreshTokenReused   = errors.New("refresh token has already been used")
+	ErrTokenRevoked         = errors.New("token has been revoked")
+	ErrUserBanned           = errors.New("user is banned")
+	ErrTokenScope           = errors.New("token is not valid for this operation")
+	ErrInvalidMFACode       = errors.New("invalid two-factor authentication code")
+	ErrTwoFactorRequired    = errors.New("two-factor authentication is required for this account")
+	ErrTwoFactorDisabled    = errors.New("two-factor authentication is not enabled")
+	ErrTooManyAttempts      = errors.New("too many failed attempts, try again later")
+	ErrCodeInvalidated      = errors.New("too many wrong attempts, request a new verification code")
+	ErrRateLimited          = errors.New("rate limit exceeded, try again later")
+	ErrOIDCNotConfigured    = errors.New("sign in with an external provider is not configured")
+	ErrInvalidOIDCState     = errors.New("invalid or expired state, sign in again")
+	ErrOIDCEmailNotVerified = errors.New("the provider did not return a verified email")
+	ErrOIDCEmailTooLong     = errors.New("the email returned by the provider is longer than 50 characters")
+	ErrOIDCAccountExists    = errors.New("an account with the email exists whose email is not verified, sign in with its password")
+	ErrInvalidScope         = errors.New("scope is unknown or not a permission of the user")
+	ErrExpiryInPast         = errors.New("expires_at must be in the future")
+	ErrMagicLinkUsed        = errors.New("login link has already been used or has expired")
 )
 
 type handlerV1 struct {
 	cfg      *config.Config
 	storage  storage.StorageI
 	inMemory storage.InMemoryStorageI
+	payments payments.PaymentProvider
+	keys     *keyring.KeyRing
+	limiters *limiters
+	oidc     *oidc.Provider
 }
 
 type HandlerV1Options struct {
 	Cfg      *config.Config
 	Storage  storage.StorageI
 	InMemory storage.InMemoryStorageI
+	Payments payments.PaymentProvider
+	Keys     *keyring.KeyRing
 }
 
 func New(options *HandlerV1Options) *handlerV1 {
@@ -36,6 +73,16 @@ func New(options *HandlerV1Options) *handlerV1 {
 		cfg:      options.Cfg,
 		storage:  options.Storage,
 		inMemory: options.InMemory,
+		payments: options.Payments,
+		keys:     options.Keys,
+		limiters: newLimiters(options.InMemory),
+		oidc: newOIDCProvider(&oidc.Config{
+			Issuer:       options.Cfg.OIDC.Issuer,
+			ClientID:     options.Cfg.OIDC.ClientID,
+			ClientSecret: options.Cfg.OIDC.ClientSecret,
+			RedirectURL:  options.Cfg.OIDC.RedirectURL,
+			Scopes:       []string{"email", "profile"},
+		}),
 	}
 }
 
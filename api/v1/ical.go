@@ -0,0 +1,259 @@
+package v1
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/ical"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const icalProdID = "-//booking-service//rooms//EN"
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id}/calendar-token [post]
+// @Summary Create a calendar feed token
+// @Description Create the secret token of the iCal feed of a room. Creating a new token revokes the previous one.
+// @Tags ical
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Success 201 {object} models.RoomCalendarTokenResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateRoomCalendarToken(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, id)
+	if !ok {
+		return
+	}
+
+	b := make([]byte, 32)
+
+	_, err = rand.Read(b)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	token := hex.EncodeToString(b)
+
+	err = h.storage.Room().SetCalendarToken(ctx.Request.Context(), id, token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.RoomCalendarTokenResponse{
+		Token:   token,
+		FeedURL: fmt.Sprintf("/v1/rooms/%d/calendar.ics?token=%s", id, token),
+	})
+}
+
+// @Router /rooms/{id}/calendar.ics [get]
+// @Summary Get the iCal feed of a room
+// @Description Export the current and upcoming bookings and manual blocks of a room as iCalendar
+// @Tags ical
+// @Produce text/calendar
+// @Param id path int true "Room ID"
+// @Param token query string true "Token"
+// @Success 200 {string} string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetRoomCalendarFeed(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	token, err := h.storage.Room().GetCalendarToken(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if token == nil || subtle.ConstantTimeCompare([]byte(*token), []byte(ctx.Query("token"))) != 1 {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return
+	}
+
+	room, err := h.storage.Room().Get(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	bookings, err := h.storage.Booking().GetRoomStays(ctx.Request.Context(), &repo.GetRoomStaysParams{
+		RoomID:   id,
+		From:     today,
+		Statuses: repo.RevenueBookingStatuses,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	blocks, err := h.storage.RoomBlock().GetAll(ctx.Request.Context(), &repo.GetRoomBlocksParams{
+		RoomID: id,
+		From:   today,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	calendar := ical.Calendar{
+		ProdID: icalProdID,
+		Name:   room.Title,
+		Events: make([]*ical.Event, 0, len(bookings)+len(blocks)),
+	}
+
+	for _, booking := range bookings {
+		calendar.Events = append(calendar.Events, &ical.Event{
+			UID:     fmt.Sprintf("booking-%d@booking-service", booking.ID),
+			Start:   booking.CheckIn,
+			End:     booking.CheckOut,
+			Summary: "Reserved",
+		})
+	}
+
+	for _, block := range blocks {
+		// Blocks imported from other channels are not exported back to them
+		if block.Source == repo.RoomBlockSourceICal {
+			continue
+		}
+
+		summary := block.Reason
+		if summary == "" {
+			summary = "Not available"
+		}
+
+		calendar.Events = append(calendar.Events, &ical.Event{
+			UID:     fmt.Sprintf("block-%d@booking-service", block.ID),
+			Start:   block.StartDate,
+			End:     block.EndDate,
+			Summary: summary,
+		})
+	}
+
+	ctx.Header("Content-Type", "text/calendar; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=\"room-%d.ics\"", id))
+	ctx.Status(http.StatusOK)
+
+	// The status is already sent, so a failed write can only be dropped
+	_ = ical.Encode(ctx.Writer, &calendar, time.Now())
+}
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id}/calendar/import [post]
+// @Summary Import an iCal file
+// @Description Block the dates of the events of an iCal file exported by another channel. Events are matched by UID, so importing a newer version of the same file updates the blocks, and cancelled events remove them.
+// @Tags ical
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param file formData file true "File"
+// @Success 200 {object} models.ImportRoomCalendarResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) ImportRoomCalendar(ctx *gin.Context) {
+	var file File
+
+	err := ctx.ShouldBind(&file)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, id)
+	if !ok {
+		return
+	}
+
+	f, err := file.File.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer f.Close()
+
+	calendar, err := ical.Parse(f)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	req := repo.ImportRoomBlocks{
+		RoomID:        id,
+		Blocks:        make([]*repo.RoomBlock, 0),
+		CancelledUIDs: make([]string, 0),
+	}
+
+	for _, event := range calendar.Events {
+		if event.Status == ical.StatusCancelled {
+			req.CancelledUIDs = append(req.CancelledUIDs, event.UID)
+			continue
+		}
+
+		uid := event.UID
+
+		req.Blocks = append(req.Blocks, &repo.RoomBlock{
+			RoomID:    id,
+			StartDate: event.Start,
+			EndDate:   event.End,
+			Reason:    event.Summary,
+			Source:    repo.RoomBlockSourceICal,
+			UID:       &uid,
+		})
+	}
+
+	result, err := h.storage.RoomBlock().Import(ctx.Request.Context(), &req)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.ImportRoomCalendarResponse{
+		Created:   result.Created,
+		Updated:   result.Updated,
+		Unchanged: result.Unchanged,
+		Removed:   result.Removed,
+	})
+}
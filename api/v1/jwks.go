@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Router /.well-known/jwks.json [get]
+// @Summary Get the JSON Web Key Set
+// @Description Get the public keys which verify access tokens, identified by the kid header of the token
+// @Tags auth
+// @Produce json
+// @Success 200 {object} keyring.JWKSet
+func (h *handlerV1) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.keys.JWKS())
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/storage"
 	"github.com/ibrat-muslim/booking-service/storage/repo"
 )
 
@@ -36,7 +37,7 @@ func (h *handlerV1) CreateOrUpdateLike(ctx *gin.Context) {
 		return
 	}
 
-	err = h.storage.Like().CreateOrUpdate(&repo.Like{
+	err = storage.ToggleLike(ctx.Request.Context(), h.storage, &repo.Like{
 		PostID: req.PostID,
 		UserID: payload.UserID,
 		Status: req.Status,
@@ -76,7 +77,7 @@ func (h *handlerV1) GetLike(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.Like().Get(postID, payload.UserID)
+	resp, err := h.storage.Like().Get(ctx.Request.Context(), postID, payload.UserID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/pkg/lockout"
+	"github.com/ibrat-muslim/booking-service/storage"
+)
+
+const (
+	// maxCodeAttempts is the number of wrong tries after which a
+	// verification code is invalidated
+	maxCodeAttempts = 5
+	codeAttemptsKey = "code_attempts_"
+)
+
+// accountLockout counts the failures against a single account, identified
+// by its email or ID
+var accountLockout = lockout.Config{
+	MaxAttempts:  5,
+	Window:       15 * time.Minute,
+	BaseLockout:  time.Minute,
+	MaxLockout:   time.Hour,
+	MemoryWindow: 24 * time.Hour,
+}
+
+// ipLockout counts the failures from a single client, which may try many
+// accounts
+var ipLockout = lockout.Config{
+	MaxAttempts:  20,
+	Window:       15 * time.Minute,
+	BaseLockout:  time.Minute,
+	MaxLockout:   time.Hour,
+	MemoryWindow: 24 * time.Hour,
+}
+
+type limiters struct {
+	email *lockout.Limiter
+	user  *lockout.Limiter
+	ip    *lockout.Limiter
+}
+
+func newLimiters(inMemory storage.InMemoryStorageI) *limiters {
+	return &limiters{
+		email: lockout.New(inMemory, "lockout_email_", accountLockout),
+		user:  lockout.New(inMemory, "lockout_user_", accountLockout),
+		ip:    lockout.New(inMemory, "lockout_ip_", ipLockout),
+	}
+}
+
+func tooManyAttempts(ctx *gin.Context, retryAfter time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(seconds(retryAfter)))
+	ctx.JSON(http.StatusTooManyRequests, errorResponse(ErrTooManyAttempts))
+}
+
+// checkLockout responds with 429 and returns false if the key or the client
+// is locked out
+func (h *handlerV1) checkLockout(ctx *gin.Context, limiter *lockout.Limiter, key string) bool {
+	retryAfter, err := limiter.Check(ctx.Request.Context(), key)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	ipRetryAfter, err := h.limiters.ip.Check(ctx.Request.Context(), ctx.ClientIP())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if ipRetryAfter > retryAfter {
+		retryAfter = ipRetryAfter
+	}
+
+	if retryAfter > 0 {
+		tooManyAttempts(ctx, retryAfter)
+		return false
+	}
+
+	return true
+}
+
+// failAttempt records a failed attempt against the key and the client, and
+// responds with status and err, or with 429 if the failure started a lockout
+func (h *handlerV1) failAttempt(ctx *gin.Context, limiter *lockout.Limiter, key string, status int, err error) {
+	retryAfter, lockErr := limiter.Fail(ctx.Request.Context(), key)
+	if lockErr != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(lockErr))
+		return
+	}
+
+	ipRetryAfter, lockErr := h.limiters.ip.Fail(ctx.Request.Context(), ctx.ClientIP())
+	if lockErr != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(lockErr))
+		return
+	}
+
+	if ipRetryAfter > retryAfter {
+		retryAfter = ipRetryAfter
+	}
+
+	if retryAfter > 0 {
+		tooManyAttempts(ctx, retryAfter)
+		return
+	}
+
+	ctx.JSON(status, errorResponse(err))
+}
+
+// wrongCode counts a wrong try of the verification code stored at codeKey
+// and invalidates the code after maxCodeAttempts of them
+func (h *handlerV1) wrongCode(ctx *gin.Context, codeKey, email string) {
+	attempts, err := h.inMemory.Incr(ctx.Request.Context(), codeAttemptsKey+codeKey, verificationCodeTTL)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	respErr := ErrIncorrectCode
+
+	if attempts >= maxCodeAttempts {
+		err = h.inMemory.Delete(ctx.Request.Context(), codeKey)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		respErr = ErrCodeInvalidated
+	}
+
+	h.failAttempt(ctx, h.limiters.email, email, http.StatusForbidden, respErr)
+}
@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	emailPkg "github.com/ibrat-muslim/booking-service/pkg/email"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const (
+	magicLinkKey = "magic_link_"
+	// magicLinkTTL is how long a login link can be used
+	magicLinkTTL = 15 * time.Minute
+
+	magicLinkCooldownKey = "magic_link_cooldown_"
+	// magicLinkCooldown is how long to wait before another link can be sent
+	// to the same email
+	magicLinkCooldown = time.Minute
+)
+
+// @Router /auth/magic-link [post]
+// @Summary Request a login link
+// @Description Sends a link to the email of the user which signs in without a password. The link can be used once. The response does not tell whether the email belongs to a user, and a link can be requested for an email once a minute.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body models.MagicLinkRequest true "Data"
+// @Success 201 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) RequestMagicLink(ctx *gin.Context) {
+
+	var req models.MagicLinkRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// The cooldown is taken before the user is looked up, so that unknown
+	// emails are answered the same way
+	cooldownKey := magicLinkCooldownKey + strings.ToLower(req.Email)
+
+	ok, err := h.inMemory.SetNX(ctx.Request.Context(), cooldownKey, "1", magicLinkCooldown)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !ok {
+		retryAfter, err := h.inMemory.TTL(ctx.Request.Context(), cooldownKey)
+		if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		ctx.Header("Retry-After", strconv.Itoa(seconds(retryAfter)))
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(ErrRateLimited))
+		return
+	}
+
+	user, err := h.storage.User().GetByEmail(ctx.Request.Context(), req.Email)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Unknown and banned users get no link but the same response
+	if err == nil && user.BannedAt == nil {
+		go func() {
+			err := h.sendMagicLink(context.Background(), user)
+			if err != nil {
+				fmt.Printf("failed to send login link: %v", err)
+			}
+		}()
+	}
+
+	ctx.JSON(http.StatusCreated, models.OKResponse{
+		Message: "Login link has been sent!",
+	})
+}
+
+// sendMagicLink emails a login link to the user. The link carries a signed
+// token whose ID is kept in memory until the link is used or expires.
+func (h *handlerV1) sendMagicLink(ctx context.Context, user *repo.User) error {
+	token, payload, err := utils.CreateToken(h.keys, &utils.TokenParams{
+		UserID:   user.ID,
+		Email:    user.Email,
+		UserType: user.Type,
+		Scope:    utils.ScopeMagicLink,
+		Duration: magicLinkTTL,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = h.inMemory.Set(ctx, magicLinkKey+payload.ID.String(), strconv.FormatInt(user.ID, 10), magicLinkTTL)
+	if err != nil {
+		return err
+	}
+
+	err = emailPkg.SendEmail(h.cfg, &emailPkg.SendEmailRequest{
+		To:      []string{user.Email},
+		Subject: "Login link",
+		Body: map[string]string{
+			"link":    h.cfg.MagicLinkURL + "?token=" + url.QueryEscape(token),
+			"minutes": strconv.Itoa(int(magicLinkTTL.Minutes())),
+		},
+		Type: emailPkg.MagicLinkEmail,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// @Router /auth/magic-link/consume [post]
+// @Summary Sign in with a login link
+// @Description Signs in with the token of a login link sent to the email of the user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body models.ConsumeMagicLinkRequest true "Data"
+// @Success 201 {object} models.AuthResponse
+// @Success 202 {object} models.MFAChallengeResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) ConsumeMagicLink(ctx *gin.Context) {
+
+	var req models.ConsumeMagicLinkRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, status, err := h.verifyToken(ctx.Request.Context(), req.Token)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	if payload.Scope != utils.ScopeMagicLink {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ErrTokenScope))
+		return
+	}
+
+	key := magicLinkKey + payload.ID.String()
+
+	// The link can be used once, so it is taken and deleted at once
+	_, err = h.inMemory.GetDel(ctx.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ErrMagicLinkUsed))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := h.storage.User().Get(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(utils.ErrInvalidToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.BannedAt != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrUserBanned))
+		return
+	}
+
+	h.completeLogin(ctx, user, http.StatusCreated)
+}
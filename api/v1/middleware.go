@@ -1,8 +1,13 @@
 package v1
 
 import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ibrat-muslim/booking-service/pkg/utils"
@@ -11,9 +16,23 @@ import (
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationPayloadKey = "authorization_payload"
+	// apiKeyAuthType is the type of authorization headers holding an API key
+	apiKeyAuthType = "ApiKey "
 )
 
 func (h *handlerV1) AuthMiddleware(c *gin.Context) {
+	h.authenticate(c)
+}
+
+// ScopedAuthMiddleware is AuthMiddleware which also accepts tokens limited
+// to one of the scopes. API keys are accepted with utils.ScopeAPIKey.
+func (h *handlerV1) ScopedAuthMiddleware(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		h.authenticate(c, scopes...)
+	}
+}
+
+func (h *handlerV1) authenticate(c *gin.Context, scopes ...string) {
 	accessToken := c.GetHeader(authorizationHeaderKey)
 
 	if len(accessToken) == 0 {
@@ -22,9 +41,24 @@ func (h *handlerV1) AuthMiddleware(c *gin.Context) {
 		return
 	}
 
-	payload, err := utils.VerifyToken(h.cfg, accessToken)
+	var (
+		payload *utils.Payload
+		status  int
+		err     error
+	)
+
+	if strings.HasPrefix(accessToken, apiKeyAuthType) {
+		payload, status, err = h.verifyAPIKey(c.Request.Context(), strings.TrimPrefix(accessToken, apiKeyAuthType))
+	} else {
+		payload, status, err = h.verifyToken(c.Request.Context(), accessToken)
+	}
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+		c.AbortWithStatusJSON(status, errorResponse(err))
+		return
+	}
+
+	if payload.Scope != "" && !containsString(scopes, payload.Scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ErrTokenScope))
 		return
 	}
 
@@ -32,6 +66,107 @@ func (h *handlerV1) AuthMiddleware(c *gin.Context) {
 	c.Next()
 }
 
+// verifyToken verifies the token and checks that it was not revoked. On
+// failure it returns the status to respond with.
+func (h *handlerV1) verifyToken(ctx context.Context, token string) (*utils.Payload, int, error) {
+	payload, err := utils.VerifyToken(h.keys, token)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	revoked, err := h.isTokenRevoked(ctx, payload)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if revoked {
+		return nil, http.StatusUnauthorized, ErrTokenRevoked
+	}
+
+	return payload, http.StatusOK, nil
+}
+
+// verifyAPIKey checks the key and returns the payload of its user, limited
+// to its scopes. On failure it returns the status to respond with.
+func (h *handlerV1) verifyAPIKey(ctx context.Context, key string) (*utils.Payload, int, error) {
+	prefix, err := utils.ParseAPIKey(key)
+	if err != nil {
+		return nil, http.StatusUnauthorized, err
+	}
+
+	apiKey, err := h.storage.APIKey().GetByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, http.StatusUnauthorized, utils.ErrInvalidToken
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(utils.HashAPIKey(key)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, http.StatusUnauthorized, utils.ErrInvalidToken
+	}
+
+	if apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt) {
+		return nil, http.StatusUnauthorized, utils.ErrExpiredToken
+	}
+
+	user, err := h.storage.User().Get(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if user.BannedAt != nil {
+		return nil, http.StatusUnauthorized, ErrUserBanned
+	}
+
+	// As with refreshing a token, keys stop working for users who must use
+	// two-factor authentication until they enable it
+	required, err := h.isTwoFactorRequired(ctx, user.Type)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	if required {
+		enabled, err := h.isTwoFactorEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+
+		if !enabled {
+			return nil, http.StatusUnauthorized, ErrTwoFactorRequired
+		}
+	}
+
+	err = h.storage.APIKey().Touch(ctx, apiKey.ID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	payload := utils.Payload{
+		UserID:      user.ID,
+		Email:       user.Email,
+		UserType:    user.Type,
+		Scope:       utils.ScopeAPIKey,
+		Permissions: apiKey.Scopes,
+		IssuedAt:    apiKey.CreatedAt,
+	}
+
+	if apiKey.ExpiresAt != nil {
+		payload.ExpiredAt = *apiKey.ExpiresAt
+	}
+
+	return &payload, http.StatusOK, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *handlerV1) GetAuthPayload(ctx *gin.Context) (*utils.Payload, error) {
 	i, exists := ctx.Get(authorizationPayloadKey)
 	if !exists {
@@ -0,0 +1,268 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/pkg/oidc"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const (
+	oidcStateKey = "oidc_state_"
+	// oidcStateTTL is how long the user has to sign in with the provider
+	oidcStateTTL = 10 * time.Minute
+	// maxNameLength is the length of the name columns of users
+	maxNameLength = 30
+	// maxEmailLength is the length of the email columns of users and
+	// identities
+	maxEmailLength = 50
+)
+
+// oidcState is kept between sending the user to the provider and the
+// callback
+type oidcState struct {
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+func newOIDCProvider(cfg *oidc.Config) *oidc.Provider {
+	if cfg.Issuer == "" {
+		return nil
+	}
+
+	return oidc.New(*cfg, nil)
+}
+
+// @Router /auth/oidc/login [get]
+// @Summary Sign in with the OpenID Connect provider
+// @Description Redirects to the provider, which redirects back to /auth/oidc/callback
+// @Tags auth
+// @Success 302
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) OIDCLogin(ctx *gin.Context) {
+	if h.oidc == nil {
+		ctx.JSON(http.StatusNotFound, errorResponse(ErrOIDCNotConfigured))
+		return
+	}
+
+	state, err := oidc.RandomToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	nonce, err := oidc.RandomToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	verifier, err := oidc.RandomToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	data, err := json.Marshal(oidcState{
+		Nonce:    nonce,
+		Verifier: verifier,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.inMemory.Set(ctx.Request.Context(), oidcStateKey+state, string(data), oidcStateTTL)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	url, err := h.oidc.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, url)
+}
+
+// @Router /auth/oidc/callback [get]
+// @Summary OpenID Connect callback
+// @Description Signs in the user the provider redirected back. The user is linked by verified email to an existing user whose email is verified as well, or else a new guest is created.
+// @Tags auth
+// @Produce json
+// @Param state query string true "State"
+// @Param code query string true "Authorization code"
+// @Success 201 {object} models.AuthResponse
+// @Success 202 {object} models.MFAChallengeResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) OIDCCallback(ctx *gin.Context) {
+	if h.oidc == nil {
+		ctx.JSON(http.StatusNotFound, errorResponse(ErrOIDCNotConfigured))
+		return
+	}
+
+	if ctx.Query("error") != "" {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New(ctx.Query("error")+" "+ctx.Query("error_description"))))
+		return
+	}
+
+	key := oidcStateKey + ctx.Query("state")
+
+	// The state can be used once, so it is taken and deleted at once
+	data, err := h.inMemory.GetDel(ctx.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidOIDCState))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var state oidcState
+
+	err = json.Unmarshal([]byte(data), &state)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	claims, err := h.oidc.Exchange(ctx.Query("code"), state.Verifier, state.Nonce)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	user, err := h.getOIDCUser(ctx.Request.Context(), claims)
+	if err != nil {
+		if errors.Is(err, ErrOIDCEmailNotVerified) {
+			ctx.JSON(http.StatusForbidden, errorResponse(err))
+			return
+		}
+		if errors.Is(err, ErrOIDCEmailTooLong) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, ErrOIDCAccountExists) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.BannedAt != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrUserBanned))
+		return
+	}
+
+	h.completeLogin(ctx, user, http.StatusCreated)
+}
+
+// getOIDCUser returns the user linked to the subject. A subject seen for the
+// first time is linked to the user with its email, or to a new guest. Only a
+// user who verified the email is linked, as an unverified email may have been
+// set by anyone to take over the sign-ins of its owner.
+func (h *handlerV1) getOIDCUser(ctx context.Context, claims *oidc.Claims) (*repo.User, error) {
+	provider := h.cfg.OIDC.Provider
+
+	identity, err := h.storage.Identity().Get(ctx, provider, claims.Subject)
+	if err == nil {
+		return h.storage.User().Get(ctx, identity.UserID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// Anyone may claim an unverified email
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrOIDCEmailNotVerified
+	}
+
+	// Unlike a name, an email cannot be truncated
+	if utf8.RuneCountInString(claims.Email) > maxEmailLength {
+		return nil, ErrOIDCEmailTooLong
+	}
+
+	// Emails are matched case-insensitively, so that a case variant does
+	// not make a second user
+	user, err := h.storage.User().GetByEmail(ctx, claims.Email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		user, err = h.createOIDCUser(ctx, claims)
+	case err == nil && user.EmailVerifiedAt == nil:
+		return nil, ErrOIDCAccountExists
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = h.storage.Identity().Create(ctx, &repo.Identity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		UserID:   user.ID,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (h *handlerV1) createOIDCUser(ctx context.Context, claims *oidc.Claims) (*repo.User, error) {
+	// The user signs in with the provider, so the password only has to be
+	// one nobody knows
+	password, err := oidc.RandomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	firstName := claims.GivenName
+	if firstName == "" {
+		firstName, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	// The provider has verified the email
+	verifiedAt := time.Now()
+
+	return h.storage.User().Create(ctx, &repo.User{
+		FirstName:       truncate(firstName, maxNameLength),
+		LastName:        truncate(claims.FamilyName, maxNameLength),
+		Email:           claims.Email,
+		Password:        hashedPassword,
+		Type:            repo.UserTypeGuest,
+		EmailVerifiedAt: &verifiedAt,
+	})
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
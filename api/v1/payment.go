@@ -0,0 +1,291 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/payments"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// signatureHeader carries the HMAC-SHA256 of a webhook body
+const signatureHeader = "X-Signature"
+
+const (
+	refundLockKey = "refund_lock_"
+	refundLockTTL = time.Minute
+)
+
+// @Security ApiKeyAuth
+// @Router /bookings/{id}/pay [post]
+// @Summary Pay for a booking
+// @Description Authorize and capture the total price of a pending booking. The booking is confirmed once the payment is captured.
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param id path int true "Booking ID"
+// @Param payment body models.PayBookingRequest true "Payment"
+// @Success 200 {object} models.Payment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 402 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) PayBooking(ctx *gin.Context) {
+	var req models.PayBookingRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	booking, _, ok := h.getAccessibleBooking(ctx, id)
+	if !ok {
+		return
+	}
+
+	if booking.GuestID != payload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return
+	}
+
+	if booking.Status != repo.BookingStatusPending {
+		ctx.JSON(http.StatusBadRequest, errorResponse(repo.ErrBookingNotPending))
+		return
+	}
+
+	// Claim the booking before calling the provider, so that it is neither
+	// paid twice nor cancelled while being paid
+	payment, err := h.storage.Payment().Create(ctx.Request.Context(), &repo.Payment{
+		BookingID: booking.ID,
+		Amount:    booking.TotalPrice,
+		Status:    repo.PaymentStatusPending,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrBookingNotPending) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		if errors.Is(err, repo.ErrPaymentInProgress) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	authorized, err := h.payments.Authorize(&payments.AuthorizeRequest{
+		Source:    req.PaymentToken,
+		Amount:    booking.TotalPrice,
+		Reference: strconv.FormatInt(booking.ID, 10),
+	})
+	if err != nil {
+		failErr := h.storage.Payment().Fail(ctx.Request.Context(), payment.ID)
+		if failErr != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(failErr))
+			return
+		}
+		if errors.Is(err, payments.ErrDeclined) {
+			ctx.JSON(http.StatusPaymentRequired, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.storage.Payment().Authorize(ctx.Request.Context(), payment.ID, authorized.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	captured, err := h.payments.Capture(authorized.ID, booking.TotalPrice)
+	if err != nil {
+		_, applyErr := h.storage.Payment().ApplyEvent(ctx.Request.Context(), &repo.PaymentEvent{
+			ID:                paymentEventID(repo.PaymentEventFailed, authorized.ID),
+			Type:              repo.PaymentEventFailed,
+			ProviderPaymentID: authorized.ID,
+		})
+		if applyErr != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(applyErr))
+			return
+		}
+		ctx.JSON(http.StatusPaymentRequired, errorResponse(err))
+		return
+	}
+
+	_, err = h.storage.Payment().ApplyEvent(ctx.Request.Context(), &repo.PaymentEvent{
+		ID:                paymentEventID(repo.PaymentEventCaptured, captured.ID),
+		Type:              repo.PaymentEventCaptured,
+		ProviderPaymentID: captured.ID,
+		Amount:            captured.CapturedAmount,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	payment, err = h.storage.Payment().Get(ctx.Request.Context(), payment.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parsePaymentToModel(payment))
+}
+
+// @Router /payments/webhook [post]
+// @Summary Payment provider webhook
+// @Description Apply a payment event signed by the provider with HMAC-SHA256 in the X-Signature header. Repeated deliveries of an event are ignored.
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param X-Signature header string true "Signature"
+// @Param event body models.PaymentEvent true "Event"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) PaymentWebhook(ctx *gin.Context) {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	event, err := h.payments.VerifyWebhook(body, ctx.GetHeader(signatureHeader))
+	if err != nil {
+		if errors.Is(err, payments.ErrInvalidSignature) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	applied, err := h.storage.Payment().ApplyEvent(ctx.Request.Context(), &repo.PaymentEvent{
+		ID:                event.ID,
+		Type:              event.Type,
+		ProviderPaymentID: event.PaymentID,
+		Amount:            event.Amount,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	message := "successfully applied"
+	if !applied {
+		message = "already applied"
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: message,
+	})
+}
+
+// refundPayment pays back the part of the refund of the cancelled booking
+// which has not been refunded yet. Refunds of a booking are issued one at a
+// time so that a retry cannot refund twice.
+func (h *handlerV1) refundPayment(ctx context.Context, booking *repo.Booking) error {
+	if booking.RefundAmount == nil || *booking.RefundAmount <= 0 {
+		return nil
+	}
+
+	lockKey := refundLockKey + strconv.FormatInt(booking.ID, 10)
+
+	locked, err := h.inMemory.SetNX(ctx, lockKey, "1", refundLockTTL)
+	if err != nil {
+		return err
+	}
+
+	if !locked {
+		return ErrRefundInProgress
+	}
+
+	defer h.inMemory.Delete(context.Background(), lockKey)
+
+	payment, err := h.storage.Payment().GetByBooking(ctx, booking.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	if payment.Status != repo.PaymentStatusCaptured && payment.Status != repo.PaymentStatusRefunded {
+		return nil
+	}
+
+	amount := math.Round((*booking.RefundAmount-payment.RefundedAmount)*100) / 100
+	if amount <= 0 {
+		return nil
+	}
+
+	// The key is the same for every retry until the refund is recorded, so
+	// that a refund issued but not recorded is not issued again
+	idempotencyKey := fmt.Sprintf("refund:%d:%.2f", booking.ID, *booking.RefundAmount)
+
+	refunded, err := h.payments.Refund(payment.ProviderPaymentID, amount, idempotencyKey)
+	if err != nil {
+		return err
+	}
+
+	// The refund has been issued, so it is recorded even if the client has gone
+	_, err = h.storage.Payment().ApplyEvent(context.Background(), &repo.PaymentEvent{
+		ID:                paymentEventID(repo.PaymentEventRefunded, fmt.Sprintf("%s:%.2f", refunded.ID, refunded.RefundedAmount)),
+		Type:              repo.PaymentEventRefunded,
+		ProviderPaymentID: refunded.ID,
+		Amount:            refunded.RefundedAmount,
+	})
+
+	return err
+}
+
+// paymentEventID identifies an event applied directly after a provider call,
+// so that a retried call recording the same change adds no event. Webhooks
+// carry event IDs of the provider and may apply the change once more, which
+// leaves the payment as it is.
+func paymentEventID(eventType, paymentID string) string {
+	return fmt.Sprintf("%s:%s", eventType, paymentID)
+}
+
+func parsePaymentToModel(payment *repo.Payment) models.Payment {
+	return models.Payment{
+		ID:             payment.ID,
+		BookingID:      payment.BookingID,
+		Amount:         payment.Amount,
+		RefundedAmount: payment.RefundedAmount,
+		Status:         payment.Status,
+		CreatedAt:      payment.CreatedAt,
+		UpdatedAt:      payment.UpdatedAt,
+	}
+}
@@ -0,0 +1,58 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+)
+
+func authSubject(payload *utils.Payload) policy.Subject {
+	return policy.Subject{
+		ID:   payload.UserID,
+		Role: payload.UserType,
+	}
+}
+
+// keyAllows reports whether the API key of the payload, if any, has the
+// permission among its scopes
+func keyAllows(payload *utils.Payload, p policy.Permission) bool {
+	return payload.Scope != utils.ScopeAPIKey || containsString(payload.Permissions, string(p))
+}
+
+// RequirePermission lets through callers whose role has the permission, at
+// least on their own resources. It has to follow AuthMiddleware.
+func (h *handlerV1) RequirePermission(p policy.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload, err := h.GetAuthPayload(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if !policy.Can(authSubject(payload), p) || !keyAllows(payload, p) {
+			c.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ErrForbidden))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authorize checks that the caller has the permission on a resource owned by
+// ownerID. On failure the response is already written.
+func (h *handlerV1) authorize(ctx *gin.Context, p policy.Permission, ownerID int64) bool {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return false
+	}
+
+	if !policy.CanOn(authSubject(payload), p, ownerID) || !keyAllows(payload, p) {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return false
+	}
+
+	return true
+}
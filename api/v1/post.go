@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
 	"github.com/ibrat-muslim/booking-service/storage/repo"
 )
 
@@ -39,7 +41,7 @@ func (h *handlerV1) CreatePost(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.Post().Create(&repo.Post{
+	resp, err := h.storage.Post().Create(ctx.Request.Context(), &repo.Post{
 		Title:       req.Title,
 		Description: req.Description,
 		ImageUrl:    req.ImageUrl,
@@ -73,7 +75,7 @@ func (h *handlerV1) GetPost(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.Post().Get(id)
+	resp, err := h.storage.Post().Get(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -85,7 +87,7 @@ func (h *handlerV1) GetPost(ctx *gin.Context) {
 
 	post := parsePostToModel(resp)
 
-	likeInfo, err := h.storage.Like().GetLikesDislikesCount(post.ID)
+	likeInfo, err := h.storage.Like().GetLikesDislikesCount(ctx.Request.Context(), post.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -173,7 +175,7 @@ func (h *handlerV1) GetPosts(ctx *gin.Context) {
 		return
 	}
 
-	result, err := h.storage.Post().GetAll(&repo.GetPostsParams{
+	result, err := h.storage.Post().GetAll(ctx.Request.Context(), &repo.GetPostsParams{
 		Limit:      request.Limit,
 		Page:       request.Page,
 		Search:     request.Search,
@@ -186,7 +188,7 @@ func (h *handlerV1) GetPosts(ctx *gin.Context) {
 		return
 	}
 
-	response, err := getPostsResponse(h, result)
+	response, err := getPostsResponse(ctx.Request.Context(), h, result)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -195,7 +197,7 @@ func (h *handlerV1) GetPosts(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, response)
 }
 
-func getPostsResponse(h *handlerV1, data *repo.GetPostsResult) (*models.GetPostsResponse, error) {
+func getPostsResponse(ctx context.Context, h *handlerV1, data *repo.GetPostsResult) (*models.GetPostsResponse, error) {
 	response := models.GetPostsResponse{
 		Posts: make([]*models.Post, 0),
 		Count: data.Count,
@@ -204,7 +206,7 @@ func getPostsResponse(h *handlerV1, data *repo.GetPostsResult) (*models.GetPosts
 	for _, post := range data.Posts {
 		p := parsePostToModel(post)
 
-		likeInfo, err := h.storage.Like().GetLikesDislikesCount(p.ID)
+		likeInfo, err := h.storage.Like().GetLikesDislikesCount(ctx, p.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -231,6 +233,7 @@ func getPostsResponse(h *handlerV1, data *repo.GetPostsResult) (*models.GetPosts
 // @Param post body models.CreatePostRequest true "Post"
 // @Success 200 {object} models.OKResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) UpdatePost(ctx *gin.Context) {
@@ -248,9 +251,23 @@ func (h *handlerV1) UpdatePost(ctx *gin.Context) {
 		return
 	}
 
+	authorID, err := h.storage.Post().GetAuthorID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !h.authorize(ctx, policy.PostUpdate, authorID) {
+		return
+	}
+
 	updatedAt := time.Now()
 
-	err = h.storage.Post().Update(&repo.Post{
+	err = h.storage.Post().Update(ctx.Request.Context(), &repo.Post{
 		ID:          id,
 		Title:       req.Title,
 		Description: req.Description,
@@ -283,6 +300,7 @@ func (h *handlerV1) UpdatePost(ctx *gin.Context) {
 // @Param id path int true "ID"
 // @Success 200 {object} models.OKResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) DeletePost(ctx *gin.Context) {
@@ -292,7 +310,21 @@ func (h *handlerV1) DeletePost(ctx *gin.Context) {
 		return
 	}
 
-	err = h.storage.Post().Delete(id)
+	authorID, err := h.storage.Post().GetAuthorID(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !h.authorize(ctx, policy.PostDelete, authorID) {
+		return
+	}
+
+	err = h.storage.Post().Delete(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -0,0 +1,361 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/pricing"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id}/pricing-rules [post]
+// @Summary Create a pricing rule
+// @Description Create a pricing rule for a room
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param rule body models.CreatePricingRuleRequest true "Pricing rule"
+// @Success 201 {object} models.PricingRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreatePricingRule(ctx *gin.Context) {
+
+	var req models.CreatePricingRuleRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	roomID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rule, err := parsePricingRuleRequest(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, roomID)
+	if !ok {
+		return
+	}
+
+	rule.RoomID = roomID
+
+	resp, err := h.storage.PricingRule().Create(ctx.Request.Context(), rule)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, parsePricingRuleToModel(resp))
+}
+
+func parsePricingRuleRequest(req *models.CreatePricingRuleRequest) (*repo.PricingRule, error) {
+	rule := repo.PricingRule{
+		Type:            req.Type,
+		DaysOfWeek:      req.DaysOfWeek,
+		Price:           req.Price,
+		Multiplier:      req.Multiplier,
+		MinNights:       req.MinNights,
+		DiscountPercent: req.DiscountPercent,
+	}
+
+	if req.StartDate != nil {
+		startDate, err := time.Parse(dateLayout, *req.StartDate)
+		if err != nil {
+			return nil, err
+		}
+		rule.StartDate = &startDate
+	}
+
+	if req.EndDate != nil {
+		endDate, err := time.Parse(dateLayout, *req.EndDate)
+		if err != nil {
+			return nil, err
+		}
+		rule.EndDate = &endDate
+	}
+
+	if (rule.StartDate == nil) != (rule.EndDate == nil) ||
+		(rule.StartDate != nil && rule.EndDate.Before(*rule.StartDate)) {
+		return nil, ErrInvalidRuleDates
+	}
+
+	var valid bool
+
+	switch rule.Type {
+	case repo.PricingRuleTypeSeason:
+		valid = rule.StartDate != nil && rule.Price != nil
+	case repo.PricingRuleTypeWeekday:
+		valid = len(rule.DaysOfWeek) > 0 && rule.Multiplier != nil
+	case repo.PricingRuleTypeMinNights:
+		valid = rule.MinNights != nil
+	case repo.PricingRuleTypeLengthOfStay:
+		valid = rule.MinNights != nil && rule.DiscountPercent != nil
+	}
+
+	if !valid {
+		return nil, ErrInvalidPricingRule
+	}
+
+	return &rule, nil
+}
+
+// @Router /rooms/{id}/pricing-rules [get]
+// @Summary Get pricing rules of a room
+// @Description Get pricing rules of a room
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Success 200 {object} models.GetPricingRulesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetPricingRules(ctx *gin.Context) {
+	roomID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rules, err := h.storage.PricingRule().GetAll(ctx.Request.Context(), roomID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := models.GetPricingRulesResponse{
+		PricingRules: make([]*models.PricingRule, 0),
+	}
+
+	for _, rule := range rules {
+		r := parsePricingRuleToModel(rule)
+		response.PricingRules = append(response.PricingRules, &r)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Security ApiKeyAuth
+// @Router /pricing-rules/{id} [delete]
+// @Summary Delete a pricing rule
+// @Description Delete a pricing rule
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeletePricingRule(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	rule, err := h.storage.PricingRule().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, rule.RoomID)
+	if !ok {
+		return
+	}
+
+	err = h.storage.PricingRule().Delete(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
+
+// @Router /rooms/{id}/quote [post]
+// @Summary Get a quote for a stay
+// @Description Get the itemised price of a stay without reserving the room
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param quote body models.QuoteRequest true "Stay"
+// @Success 200 {object} models.QuoteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetRoomQuote(ctx *gin.Context) {
+	var req models.QuoteRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	checkIn, checkOut, err := parseStayDates(req.CheckIn, req.CheckOut)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	room, err := h.storage.Room().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.GuestsCount > room.Capacity {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrCapacityExceeded))
+		return
+	}
+
+	quote, err := h.quoteStay(ctx.Request.Context(), room, checkIn, checkOut)
+	if err != nil {
+		var minNightsErr *pricing.MinNightsError
+		if errors.As(err, &minNightsErr) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.QuoteResponse{
+		RoomID:   room.ID,
+		CheckIn:  req.CheckIn,
+		CheckOut: req.CheckOut,
+		Quote:    parseQuoteToModel(quote),
+	})
+}
+
+// quoteStay prices a stay in the room using the room's pricing rules
+func (h *handlerV1) quoteStay(ctx context.Context, room *repo.Room, checkIn, checkOut time.Time) (*pricing.Quote, error) {
+	rules, err := h.storage.PricingRule().GetAll(ctx, room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return pricing.Calculate(room.BasePrice, parsePricingRules(rules), checkIn, checkOut)
+}
+
+func parsePricingRules(rules []*repo.PricingRule) []*pricing.Rule {
+	result := make([]*pricing.Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		r := pricing.Rule{
+			Type:      rule.Type,
+			StartDate: rule.StartDate,
+			EndDate:   rule.EndDate,
+		}
+
+		for _, day := range rule.DaysOfWeek {
+			r.DaysOfWeek = append(r.DaysOfWeek, time.Weekday(day))
+		}
+
+		if rule.Price != nil {
+			r.Price = *rule.Price
+		}
+
+		if rule.Multiplier != nil {
+			r.Multiplier = *rule.Multiplier
+		}
+
+		if rule.MinNights != nil {
+			r.MinNights = int(*rule.MinNights)
+		}
+
+		if rule.DiscountPercent != nil {
+			r.DiscountPercent = *rule.DiscountPercent
+		}
+
+		result = append(result, &r)
+	}
+
+	return result
+}
+
+func parseQuoteToModel(quote *pricing.Quote) models.Quote {
+	result := models.Quote{
+		Nights:          make([]*models.QuoteNight, 0),
+		Subtotal:        quote.Subtotal,
+		DiscountPercent: quote.DiscountPercent,
+		Discount:        quote.Discount,
+		Total:           quote.Total,
+	}
+
+	for _, night := range quote.Nights {
+		result.Nights = append(result.Nights, &models.QuoteNight{
+			Date:  night.Date.Format(dateLayout),
+			Price: night.Price,
+		})
+	}
+
+	return result
+}
+
+func parsePricingRuleToModel(rule *repo.PricingRule) models.PricingRule {
+	result := models.PricingRule{
+		ID:              rule.ID,
+		RoomID:          rule.RoomID,
+		Type:            rule.Type,
+		DaysOfWeek:      rule.DaysOfWeek,
+		Price:           rule.Price,
+		Multiplier:      rule.Multiplier,
+		MinNights:       rule.MinNights,
+		DiscountPercent: rule.DiscountPercent,
+		CreatedAt:       rule.CreatedAt,
+	}
+
+	if rule.StartDate != nil {
+		startDate := rule.StartDate.Format(dateLayout)
+		result.StartDate = &startDate
+	}
+
+	if rule.EndDate != nil {
+		endDate := rule.EndDate.Format(dateLayout)
+		result.EndDate = &endDate
+	}
+
+	return result
+}
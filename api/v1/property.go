@@ -0,0 +1,365 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /properties [post]
+// @Summary Create a property
+// @Description Create a property
+// @Tags property
+// @Accept json
+// @Produce json
+// @Param property body models.CreatePropertyRequest true "Property"
+// @Success 201 {object} models.Property
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateProperty(ctx *gin.Context) {
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var req models.CreatePropertyRequest
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	policy, tiers, err := parseCancellationPolicyRequest(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	resp, err := h.storage.Property().Create(ctx.Request.Context(), &repo.Property{
+		OwnerID:            payload.UserID,
+		Title:              req.Title,
+		Description:        req.Description,
+		Address:            req.Address,
+		City:               req.City,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		Amenities:          req.Amenities,
+		CancellationPolicy: policy,
+		CancellationTiers:  tiers,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, parsePropertyToModel(resp))
+}
+
+// @Router /properties/{id} [get]
+// @Summary Get a property by id
+// @Description Get a property by id
+// @Tags property
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.Property
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetProperty(ctx *gin.Context) {
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	resp, err := h.storage.Property().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	property := parsePropertyToModel(resp)
+
+	property.RatingInfo, err = h.getPropertyRatingInfo(ctx.Request.Context(), property.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, property)
+}
+
+func validateGetPropertiesParams(ctx *gin.Context) (*models.GetPropertiesParams, error) {
+	var (
+		limit   int64 = 10
+		page    int64 = 1
+		ownerID int64
+		err     error
+	)
+
+	if ctx.Query("limit") != "" {
+		limit, err = strconv.ParseInt(ctx.Query("limit"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("page") != "" {
+		page, err = strconv.ParseInt(ctx.Query("page"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Query("owner_id") != "" {
+		ownerID, err = strconv.ParseInt(ctx.Query("owner_id"), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.GetPropertiesParams{
+		Limit:   int32(limit),
+		Page:    int32(page),
+		Search:  ctx.Query("search"),
+		City:    ctx.Query("city"),
+		OwnerID: ownerID,
+	}, nil
+}
+
+// @Router /properties [get]
+// @Summary Get properties
+// @Description Get properties
+// @Tags property
+// @Accept json
+// @Produce json
+// @Param filter query models.GetPropertiesParams false "Filter"
+// @Success 200 {object} models.GetPropertiesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetProperties(ctx *gin.Context) {
+	request, err := validateGetPropertiesParams(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := h.storage.Property().GetAll(ctx.Request.Context(), &repo.GetPropertiesParams{
+		Limit:   request.Limit,
+		Page:    request.Page,
+		Search:  request.Search,
+		City:    request.City,
+		OwnerID: request.OwnerID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response, err := getPropertiesResponse(ctx.Request.Context(), h, result)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func getPropertiesResponse(ctx context.Context, h *handlerV1, data *repo.GetPropertiesResult) (*models.GetPropertiesResponse, error) {
+	response := models.GetPropertiesResponse{
+		Properties: make([]*models.Property, 0),
+		Count:      data.Count,
+	}
+
+	for _, property := range data.Properties {
+		p := parsePropertyToModel(property)
+
+		ratingInfo, err := h.getPropertyRatingInfo(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		p.RatingInfo = ratingInfo
+
+		response.Properties = append(response.Properties, &p)
+	}
+
+	return &response, nil
+}
+
+// @Security ApiKeyAuth
+// @Router /properties/{id} [put]
+// @Summary Update a property
+// @Description Update a property
+// @Tags property
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Param property body models.CreatePropertyRequest true "Property"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) UpdateProperty(ctx *gin.Context) {
+	var req models.CreatePropertyRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	policy, tiers, err := parseCancellationPolicyRequest(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedProperty(ctx, id)
+	if !ok {
+		return
+	}
+
+	updatedAt := time.Now()
+
+	err = h.storage.Property().Update(ctx.Request.Context(), &repo.Property{
+		ID:                 id,
+		Title:              req.Title,
+		Description:        req.Description,
+		Address:            req.Address,
+		City:               req.City,
+		Latitude:           req.Latitude,
+		Longitude:          req.Longitude,
+		Amenities:          req.Amenities,
+		UpdatedAt:          &updatedAt,
+		CancellationPolicy: policy,
+		CancellationTiers:  tiers,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully updated",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /properties/{id} [delete]
+// @Summary Delete a property
+// @Description Delete a property
+// @Tags property
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeleteProperty(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedProperty(ctx, id)
+	if !ok {
+		return
+	}
+
+	err = h.storage.Property().Delete(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
+
+// getManagedProperty loads the property and checks that the caller is its owner
+// or a superadmin. On failure the response is already written.
+func (h *handlerV1) getManagedProperty(ctx *gin.Context, id int64) (*repo.Property, bool) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false
+	}
+
+	property, err := h.storage.Property().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false
+	}
+
+	if !canManageProperty(payload, property) {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return nil, false
+	}
+
+	return property, true
+}
+
+func canManageProperty(payload *utils.Payload, property *repo.Property) bool {
+	return policy.CanOn(authSubject(payload), policy.PropertyManage, property.OwnerID)
+}
+
+func parsePropertyToModel(property *repo.Property) models.Property {
+	return models.Property{
+		ID:                 property.ID,
+		OwnerID:            property.OwnerID,
+		Title:              property.Title,
+		Description:        property.Description,
+		Address:            property.Address,
+		City:               property.City,
+		Latitude:           property.Latitude,
+		Longitude:          property.Longitude,
+		Amenities:          property.Amenities,
+		CancellationPolicy: parseCancellationPolicyToModel(property),
+		CreatedAt:          property.CreatedAt,
+		UpdatedAt:          property.UpdatedAt,
+	}
+}
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/pkg/ratelimit"
+)
+
+// RateLimitMiddleware limits the requests of each principal, the user of
+// the access token or else the client IP, to the limit of the route. The
+// state of the limit is sent in the RateLimit-* headers.
+func (h *handlerV1) RateLimitMiddleware(limiter *ratelimit.Limiter, rules *ratelimit.Rules) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit, bucket := rules.Get(c.Request.Method, c.FullPath())
+		if limit.Requests == 0 {
+			c.Next()
+			return
+		}
+
+		principal, err := h.rateLimitPrincipal(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		result, err := limiter.Allow(c.Request.Context(), bucket+"_"+principal, limit)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		reset := seconds(result.Reset)
+
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Requests, seconds(limit.Window)))
+		c.Header("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		c.Header("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		c.Header("RateLimit-Reset", strconv.Itoa(reset))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(reset))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, errorResponse(ErrRateLimited))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// authRoutesPrefix starts the paths of the routes which sign users in. They
+// are limited per client, so that tokens of other accounts do not add to
+// the allowance of a client.
+const authRoutesPrefix = "/v1/auth/"
+
+// rateLimitPrincipal identifies the user of a valid access token, or else
+// the client. The middleware runs before authentication, so the token is
+// verified here, and scoped or revoked tokens count as no token.
+func (h *handlerV1) rateLimitPrincipal(c *gin.Context) (string, error) {
+	token := c.GetHeader(authorizationHeaderKey)
+
+	if token != "" && !strings.HasPrefix(c.FullPath(), authRoutesPrefix) {
+		payload, status, err := h.verifyToken(c.Request.Context(), token)
+		if status == http.StatusInternalServerError {
+			return "", err
+		}
+		if err == nil && payload.Scope == "" {
+			return "user_" + strconv.FormatInt(payload.UserID, 10), nil
+		}
+	}
+
+	return "ip_" + c.ClientIP(), nil
+}
+
+// seconds rounds d up to whole seconds
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,207 @@
+package v1
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const reportFormatCSV = "csv"
+
+// maxReportMonths bounds the range of a report, as every month of it is
+// computed for every property
+const maxReportMonths = 24
+
+var reportCSVHeader = []string{
+	"property_id",
+	"property_title",
+	"month",
+	"available_nights",
+	"booked_nights",
+	"occupancy_rate",
+	"revenue",
+	"adr",
+	"revpar",
+	"bookings",
+	"cancellations",
+	"average_lead_time",
+}
+
+func validateGetReportParams(ctx *gin.Context) (*models.GetReportParams, time.Time, time.Time, error) {
+	var (
+		propertyID int64
+		err        error
+	)
+
+	// The last twelve months including the current one by default
+	now := time.Now().UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	from := monthStart.AddDate(0, -11, 0)
+	to := monthStart.AddDate(0, 1, -1)
+
+	if ctx.Query("from") != "" {
+		from, err = time.Parse(dateLayout, ctx.Query("from"))
+		if err != nil {
+			return nil, from, to, err
+		}
+	}
+
+	if ctx.Query("to") != "" {
+		to, err = time.Parse(dateLayout, ctx.Query("to"))
+		if err != nil {
+			return nil, from, to, err
+		}
+	}
+
+	if to.Before(from) || !to.Before(from.AddDate(0, maxReportMonths, 0)) {
+		return nil, from, to, ErrInvalidReportRange
+	}
+
+	if ctx.Query("property_id") != "" {
+		propertyID, err = strconv.ParseInt(ctx.Query("property_id"), 10, 64)
+		if err != nil {
+			return nil, from, to, err
+		}
+	}
+
+	return &models.GetReportParams{
+		From:       from.Format(dateLayout),
+		To:         to.Format(dateLayout),
+		PropertyID: propertyID,
+		Format:     ctx.Query("format"),
+	}, from, to, nil
+}
+
+// @Security ApiKeyAuth
+// @Router /owner/reports [get]
+// @Summary Get owner reports
+// @Description Get occupancy rate, revenue, ADR, RevPAR, cancellations and average lead time in days of the caller's properties per month. Both from and to are inclusive, span at most 24 months and default to the last twelve months. Use format=csv to download the report as CSV.
+// @Tags report
+// @Accept json
+// @Produce json,text/csv
+// @Param filter query models.GetReportParams false "Filter"
+// @Success 200 {object} models.GetReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetOwnerReports(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	request, from, to, err := validateGetReportParams(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	params := repo.GetReportParams{
+		PropertyID: request.PropertyID,
+		From:       from,
+		To:         to.AddDate(0, 0, 1),
+	}
+
+	if request.PropertyID != 0 {
+		_, ok := h.getManagedProperty(ctx, request.PropertyID)
+		if !ok {
+			return
+		}
+	}
+
+	if policy.GrantOf(authSubject(payload), policy.ReportRead) == policy.Own {
+		params.OwnerID = payload.UserID
+	}
+
+	result, err := h.storage.Report().GetPropertyMonthly(ctx.Request.Context(), &params)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := models.GetReportResponse{
+		From:    request.From,
+		To:      request.To,
+		Reports: make([]*models.PropertyMonthReport, 0),
+	}
+
+	for _, report := range result {
+		r := parsePropertyMonthReportToModel(report)
+		response.Reports = append(response.Reports, &r)
+	}
+
+	if request.Format == reportFormatCSV {
+		writeReportCSV(ctx, &response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func writeReportCSV(ctx *gin.Context, response *models.GetReportResponse) {
+	filename := fmt.Sprintf("report_%s_%s.csv", response.From, response.To)
+
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+
+	records := [][]string{reportCSVHeader}
+
+	for _, r := range response.Reports {
+		records = append(records, []string{
+			strconv.FormatInt(r.PropertyID, 10),
+			escapeCSVCell(r.PropertyTitle),
+			r.Month,
+			strconv.FormatInt(r.AvailableNights, 10),
+			strconv.FormatInt(r.BookedNights, 10),
+			strconv.FormatFloat(r.OccupancyRate, 'f', 4, 64),
+			strconv.FormatFloat(r.Revenue, 'f', 2, 64),
+			strconv.FormatFloat(r.ADR, 'f', 2, 64),
+			strconv.FormatFloat(r.RevPAR, 'f', 2, 64),
+			strconv.FormatInt(r.Bookings, 10),
+			strconv.FormatInt(r.Cancellations, 10),
+			strconv.FormatFloat(r.AverageLeadTime, 'f', 1, 64),
+		})
+	}
+
+	// The status is already sent, so a failed write can only be dropped
+	_ = w.WriteAll(records)
+}
+
+// escapeCSVCell prefixes text which spreadsheets would run as a formula, as
+// titles are written by owners
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func parsePropertyMonthReportToModel(report *repo.PropertyMonthReport) models.PropertyMonthReport {
+	return models.PropertyMonthReport{
+		PropertyID:      report.PropertyID,
+		PropertyTitle:   report.PropertyTitle,
+		Month:           report.Month.Format(monthLayout),
+		AvailableNights: report.AvailableNights,
+		BookedNights:    report.BookedNights,
+		OccupancyRate:   report.OccupancyRate,
+		Revenue:         report.Revenue,
+		ADR:             report.ADR,
+		RevPAR:          report.RevPAR,
+		Bookings:        report.Bookings,
+		Cancellations:   report.Cancellations,
+		AverageLeadTime: report.AverageLeadTime,
+	}
+}
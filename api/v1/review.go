@@ -0,0 +1,240 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /reviews [post]
+// @Summary Create a review
+// @Description Review the property of a completed booking. Each booking can be reviewed once by its guest.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param review body models.CreateReviewRequest true "Review"
+// @Success 201 {object} models.Review
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateReview(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var req models.CreateReviewRequest
+
+	err = ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	booking, err := h.storage.Booking().Get(ctx.Request.Context(), req.BookingID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if booking.GuestID != payload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return
+	}
+
+	if booking.Status != repo.BookingStatusCompleted {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrStayNotCompleted))
+		return
+	}
+
+	room, err := h.storage.Room().Get(ctx.Request.Context(), booking.RoomID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	resp, err := h.storage.Review().Create(ctx.Request.Context(), &repo.Review{
+		BookingID:  booking.ID,
+		PropertyID: room.PropertyID,
+		GuestID:    payload.UserID,
+		Rating:     req.Rating,
+		Text:       req.Text,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrReviewExists) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, parseReviewToModel(resp))
+}
+
+// @Router /properties/{id}/reviews [get]
+// @Summary Get reviews of a property
+// @Description Get reviews of a property
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param filter query models.GetAllParamsRequest false "Filter"
+// @Success 200 {object} models.GetReviewsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetPropertyReviews(ctx *gin.Context) {
+	propertyID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, err := validateGetAllParamsRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := h.storage.Review().GetAll(ctx.Request.Context(), &repo.GetReviewsParams{
+		Limit:      request.Limit,
+		Page:       request.Page,
+		PropertyID: propertyID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := models.GetReviewsResponse{
+		Reviews: make([]*models.Review, 0),
+		Count:   result.Count,
+	}
+
+	for _, review := range result.Reviews {
+		r := parseReviewToModel(review)
+		response.Reviews = append(response.Reviews, &r)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Security ApiKeyAuth
+// @Router /reviews/{id}/reply [post]
+// @Summary Reply to a review
+// @Description The owner of the property may publicly reply to a review once
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Param reply body models.ReplyReviewRequest true "Reply"
+// @Success 200 {object} models.Review
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) ReplyReview(ctx *gin.Context) {
+	var req models.ReplyReviewRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	review, err := h.storage.Review().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedProperty(ctx, review.PropertyID)
+	if !ok {
+		return
+	}
+
+	err = h.storage.Review().Reply(ctx.Request.Context(), &repo.ReplyReview{
+		ID:        id,
+		Reply:     req.Reply,
+		RepliedAt: time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		if errors.Is(err, repo.ErrReplyExists) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	review, err = h.storage.Review().Get(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parseReviewToModel(review))
+}
+
+func (h *handlerV1) getPropertyRatingInfo(ctx context.Context, propertyID int64) (*models.PropertyRatingInfo, error) {
+	summary, err := h.storage.Review().GetRatingSummary(ctx, propertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PropertyRatingInfo{
+		Average: summary.Average,
+		Count:   summary.Count,
+		Histogram: map[int32]int64{
+			1: summary.OneStar,
+			2: summary.TwoStars,
+			3: summary.ThreeStars,
+			4: summary.FourStars,
+			5: summary.FiveStars,
+		},
+	}, nil
+}
+
+func parseReviewToModel(review *repo.Review) models.Review {
+	return models.Review{
+		ID:         review.ID,
+		BookingID:  review.BookingID,
+		PropertyID: review.PropertyID,
+		GuestID:    review.GuestID,
+		Rating:     review.Rating,
+		Text:       review.Text,
+		Reply:      review.Reply,
+		RepliedAt:  review.RepliedAt,
+		CreatedAt:  review.CreatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage"
+)
+
+const (
+	RevokedTokenKey     = "revoked_token_"
+	RevokedSessionKey   = "revoked_session_"
+	TokensValidAfterKey = "tokens_valid_after_"
+)
+
+const resetPasswordTokenTTL = 30 * time.Minute
+
+// revokeToken denies the token until it expires on its own
+func (h *handlerV1) revokeToken(ctx context.Context, payload *utils.Payload) error {
+	ttl := time.Until(payload.ExpiredAt)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return h.inMemory.Set(ctx, RevokedTokenKey+payload.ID.String(), "1", ttl)
+}
+
+// revokeSessions denies the access tokens issued for the sessions, which
+// have been revoked, until the last of them expires
+func (h *handlerV1) revokeSessions(ctx context.Context, sessionIDs ...string) error {
+	for _, id := range sessionIDs {
+		err := h.inMemory.Set(ctx, RevokedSessionKey+id, "1", h.cfg.AccessTokenTTL)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// revokeUserTokens denies every token issued to the user so far, ends all of
+// the user's sessions and deletes the user's API keys
+func (h *handlerV1) revokeUserTokens(ctx context.Context, userID int64) error {
+	// Tokens issued earlier than this are expired once the key is gone
+	ttl := h.cfg.AccessTokenTTL
+	if ttl < resetPasswordTokenTTL {
+		ttl = resetPasswordTokenTTL
+	}
+
+	err := h.inMemory.Set(
+		ctx,
+		TokensValidAfterKey+strconv.FormatInt(userID, 10),
+		time.Now().UTC().Format(time.RFC3339Nano),
+		ttl,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.storage.Session().RevokeAll(ctx, userID, "")
+	if err != nil {
+		return err
+	}
+
+	return h.storage.APIKey().DeleteAll(ctx, userID)
+}
+
+func (h *handlerV1) isTokenRevoked(ctx context.Context, payload *utils.Payload) (bool, error) {
+	_, err := h.inMemory.Get(ctx, RevokedTokenKey+payload.ID.String())
+	if err == nil {
+		return true, nil
+	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		return false, err
+	}
+
+	if payload.SessionID != "" {
+		_, err = h.inMemory.Get(ctx, RevokedSessionKey+payload.SessionID)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, storage.ErrKeyNotFound) {
+			return false, err
+		}
+	}
+
+	value, err := h.inMemory.Get(ctx, TokensValidAfterKey+strconv.FormatInt(payload.UserID, 10))
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	validAfter, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return false, err
+	}
+
+	return payload.IssuedAt.Before(validAfter), nil
+}
@@ -0,0 +1,281 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /properties/{id}/rooms [post]
+// @Summary Create a room
+// @Description Create a room
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param room body models.CreateRoomRequest true "Room"
+// @Success 201 {object} models.Room
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateRoom(ctx *gin.Context) {
+
+	var req models.CreateRoomRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	propertyID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedProperty(ctx, propertyID)
+	if !ok {
+		return
+	}
+
+	resp, err := h.storage.Room().Create(ctx.Request.Context(), &repo.Room{
+		PropertyID:  propertyID,
+		Title:       req.Title,
+		Description: req.Description,
+		Capacity:    req.Capacity,
+		BasePrice:   req.BasePrice,
+		Photos:      req.Photos,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, parseRoomToModel(resp))
+}
+
+// @Router /properties/{id}/rooms [get]
+// @Summary Get rooms of a property
+// @Description Get rooms of a property
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param id path int true "Property ID"
+// @Param filter query models.GetAllParamsRequest false "Filter"
+// @Success 200 {object} models.GetRoomsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetRooms(ctx *gin.Context) {
+	propertyID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	request, err := validateGetAllParamsRequest(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	result, err := h.storage.Room().GetAll(ctx.Request.Context(), &repo.GetRoomsParams{
+		Limit:      request.Limit,
+		Page:       request.Page,
+		PropertyID: propertyID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getRoomsResponse(result))
+}
+
+func getRoomsResponse(data *repo.GetRoomsResult) *models.GetRoomsResponse {
+	response := models.GetRoomsResponse{
+		Rooms: make([]*models.Room, 0),
+		Count: data.Count,
+	}
+
+	for _, room := range data.Rooms {
+		r := parseRoomToModel(room)
+		response.Rooms = append(response.Rooms, &r)
+	}
+
+	return &response
+}
+
+// @Router /rooms/{id} [get]
+// @Summary Get a room by id
+// @Description Get a room by id
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.Room
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetRoom(ctx *gin.Context) {
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	resp, err := h.storage.Room().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parseRoomToModel(resp))
+}
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id} [put]
+// @Summary Update a room
+// @Description Update a room
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Param room body models.CreateRoomRequest true "Room"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) UpdateRoom(ctx *gin.Context) {
+	var req models.CreateRoomRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, id)
+	if !ok {
+		return
+	}
+
+	updatedAt := time.Now()
+
+	err = h.storage.Room().Update(ctx.Request.Context(), &repo.Room{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Capacity:    req.Capacity,
+		BasePrice:   req.BasePrice,
+		Photos:      req.Photos,
+		UpdatedAt:   &updatedAt,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully updated",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id} [delete]
+// @Summary Delete a room
+// @Description Delete a room
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeleteRoom(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, id)
+	if !ok {
+		return
+	}
+
+	err = h.storage.Room().Delete(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
+
+// getManagedRoom loads the room and checks that the caller may manage the
+// property it belongs to. On failure the response is already written.
+func (h *handlerV1) getManagedRoom(ctx *gin.Context, id int64) (*repo.Room, bool) {
+	room, err := h.storage.Room().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return nil, false
+	}
+
+	_, ok := h.getManagedProperty(ctx, room.PropertyID)
+	if !ok {
+		return nil, false
+	}
+
+	return room, true
+}
+
+func parseRoomToModel(room *repo.Room) models.Room {
+	return models.Room{
+		ID:          room.ID,
+		PropertyID:  room.PropertyID,
+		Title:       room.Title,
+		Description: room.Description,
+		Capacity:    room.Capacity,
+		BasePrice:   room.BasePrice,
+		Photos:      room.Photos,
+		CreatedAt:   room.CreatedAt,
+		UpdatedAt:   room.UpdatedAt,
+	}
+}
@@ -0,0 +1,205 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id}/blocks [post]
+// @Summary Block a room
+// @Description Make a room unavailable over a date range, e.g. for maintenance or personal use. The end date is exclusive.
+// @Tags room-block
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param block body models.CreateRoomBlockRequest true "Block"
+// @Success 201 {object} models.RoomBlock
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) CreateRoomBlock(ctx *gin.Context) {
+
+	var req models.CreateRoomBlockRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	roomID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	startDate, err := time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	endDate, err := time.Parse(dateLayout, req.EndDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	if !endDate.After(startDate) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidBlockDates))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, roomID)
+	if !ok {
+		return
+	}
+
+	resp, err := h.storage.RoomBlock().Create(ctx.Request.Context(), &repo.RoomBlock{
+		RoomID:    roomID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrRoomNotAvailable) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, parseRoomBlockToModel(resp))
+}
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id}/blocks [get]
+// @Summary Get blocks of a room
+// @Description Get the current and upcoming blocks of a room, both manual and imported from iCal
+// @Tags room-block
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Success 200 {object} models.GetRoomBlocksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetRoomBlocks(ctx *gin.Context) {
+	roomID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, roomID)
+	if !ok {
+		return
+	}
+
+	blocks, err := h.storage.RoomBlock().GetAll(ctx.Request.Context(), &repo.GetRoomBlocksParams{
+		RoomID: roomID,
+		From:   time.Now().UTC().Truncate(24 * time.Hour),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := models.GetRoomBlocksResponse{
+		Blocks: make([]*models.RoomBlock, 0),
+	}
+
+	for _, block := range blocks {
+		b := parseRoomBlockToModel(block)
+		response.Blocks = append(response.Blocks, &b)
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Security ApiKeyAuth
+// @Router /rooms/{id}/blocks/{block_id} [delete]
+// @Summary Delete a block
+// @Description Delete a block of a room. Blocks imported from iCal come back on the next import unless removed from the source calendar.
+// @Tags room-block
+// @Accept json
+// @Produce json
+// @Param id path int true "Room ID"
+// @Param block_id path int true "Block ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeleteRoomBlock(ctx *gin.Context) {
+	roomID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("block_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	block, err := h.storage.RoomBlock().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if block.RoomID != roomID {
+		ctx.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	_, ok := h.getManagedRoom(ctx, roomID)
+	if !ok {
+		return
+	}
+
+	err = h.storage.RoomBlock().Delete(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
+
+func parseRoomBlockToModel(block *repo.RoomBlock) models.RoomBlock {
+	return models.RoomBlock{
+		ID:        block.ID,
+		RoomID:    block.RoomID,
+		StartDate: block.StartDate.Format(dateLayout),
+		EndDate:   block.EndDate.Format(dateLayout),
+		Reason:    block.Reason,
+		Source:    block.Source,
+		CreatedAt: block.CreatedAt,
+		UpdatedAt: block.UpdatedAt,
+	}
+}
@@ -0,0 +1,370 @@
+package v1
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const maxUserAgentLength = 255
+
+// createSession starts a new session of the user and returns its access and
+// refresh tokens
+func (h *handlerV1) createSession(ctx *gin.Context, user *repo.User) (string, string, error) {
+	sessionID := uuid.NewString()
+
+	refreshToken, hash, err := utils.CreateRefreshToken(sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	_, err = h.storage.Session().Create(ctx.Request.Context(), &repo.Session{
+		ID:               sessionID,
+		UserID:           user.ID,
+		RefreshTokenHash: hash,
+		UserAgent:        truncate(ctx.Request.UserAgent(), maxUserAgentLength),
+		IPAddress:        ctx.ClientIP(),
+		ExpiresAt:        time.Now().Add(h.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	accessToken, err := h.createAccessToken(user, sessionID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+func (h *handlerV1) createAccessToken(user *repo.User, sessionID string) (string, error) {
+	token, _, err := utils.CreateToken(h.keys, &utils.TokenParams{
+		UserID:    user.ID,
+		UserType:  user.Type,
+		Email:     user.Email,
+		SessionID: sessionID,
+		Duration:  h.cfg.AccessTokenTTL,
+	})
+
+	return token, err
+}
+
+// @Router /auth/refresh [post]
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes its session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param data body models.RefreshTokenRequest true "Data"
+// @Success 200 {object} models.TokenResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) RefreshToken(ctx *gin.Context) {
+
+	var req models.RefreshTokenRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	sessionID, secret, err := utils.ParseRefreshToken(req.RefreshToken)
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return
+	}
+
+	session, err := h.storage.Session().Get(ctx.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(utils.ErrInvalidToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ErrSessionRevoked))
+		return
+	}
+
+	oldHash := utils.HashRefreshToken(secret)
+
+	// A token of the session which is not the current one has been rotated
+	// out, so it may have been stolen: end the session for every holder
+	if oldHash != session.RefreshTokenHash {
+		h.revokeReusedSession(ctx, session.ID)
+		return
+	}
+
+	refreshToken, newHash, err := utils.CreateRefreshToken(session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.storage.Session().Rotate(ctx.Request.Context(), &repo.RotateSession{
+		ID:      session.ID,
+		OldHash: oldHash,
+		NewHash: newHash,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Rotated concurrently with the same token
+			h.revokeReusedSession(ctx, session.ID)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	user, err := h.storage.User().Get(ctx.Request.Context(), session.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.BannedAt != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ErrUserBanned))
+		return
+	}
+
+	// Sessions started before two-factor authentication was required must
+	// log in again to enroll
+	required, err := h.isTwoFactorRequired(ctx.Request.Context(), user.Type)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if required {
+		enabled, err := h.isTwoFactorEnabled(ctx.Request.Context(), user.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		if !enabled {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ErrTwoFactorRequired))
+			return
+		}
+	}
+
+	accessToken, err := h.createAccessToken(user, session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	})
+}
+
+func (h *handlerV1) revokeReusedSession(ctx *gin.Context, sessionID string) {
+	err := h.storage.Session().Revoke(ctx.Request.Context(), sessionID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.revokeSessions(ctx.Request.Context(), sessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusUnauthorized, errorResponse(ErrRefreshTokenReused))
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/logout [post]
+// @Summary Logout
+// @Description Revoke the access token and its session, so that its refresh token can no longer be used
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.OKResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) Logout(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.revokeToken(ctx.Request.Context(), payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if payload.SessionID != "" {
+		err = h.storage.Session().Revoke(ctx.Request.Context(), payload.SessionID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		err = h.revokeSessions(ctx.Request.Context(), payload.SessionID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully logged out",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/sessions [get]
+// @Summary Get sessions
+// @Description Get the active sessions of the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.GetSessionsResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetSessions(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sessions, err := h.storage.Session().GetActive(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := models.GetSessionsResponse{
+		Sessions: make([]*models.Session, 0),
+	}
+
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &models.Session{
+			ID:          session.ID,
+			UserAgent:   session.UserAgent,
+			IPAddress:   session.IPAddress,
+			CreatedAt:   session.CreatedAt,
+			RefreshedAt: session.RefreshedAt,
+			ExpiresAt:   session.ExpiresAt,
+			Current:     session.ID == payload.SessionID,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/sessions [delete]
+// @Summary Delete other sessions
+// @Description Revoke every session of the current user except the current one, along with their access tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.OKResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeleteSessions(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	revoked, err := h.storage.Session().RevokeAll(ctx.Request.Context(), payload.UserID, payload.SessionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.revokeSessions(ctx.Request.Context(), revoked...)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/sessions/{id} [delete]
+// @Summary Delete a session
+// @Description Revoke a session of the current user along with its access tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DeleteSession(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	_, err = uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	session, err := h.storage.Session().Get(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Sessions of other users are reported as missing rather than forbidden
+	if session.UserID != payload.UserID {
+		ctx.JSON(http.StatusNotFound, errorResponse(sql.ErrNoRows))
+		return
+	}
+
+	err = h.storage.Session().Revoke(ctx.Request.Context(), session.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.revokeSessions(ctx.Request.Context(), session.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully deleted",
+	})
+}
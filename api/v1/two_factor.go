@@ -0,0 +1,604 @@
+package v1
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/totp"
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+const (
+	totpIssuer          = "booking-service"
+	recoveryCodesCount  = 10
+	mfaPendingTokenTTL  = 5 * time.Minute
+	mfaEnrollTokenTTL   = 15 * time.Minute
+	mfaChallengeVerify  = "verify"
+	mfaChallengeEnroll  = "enroll"
+	twoFactorRequiredOn = "true"
+)
+
+// completeLogin responds with the tokens of a new session of the user, or
+// with a challenge if the user has to pass two-factor authentication first
+func (h *handlerV1) completeLogin(ctx *gin.Context, user *repo.User, status int) {
+	enabled, err := h.isTwoFactorEnabled(ctx.Request.Context(), user.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if enabled {
+		h.respondMFAChallenge(ctx, user, mfaChallengeVerify, utils.ScopeMFAPending, mfaPendingTokenTTL)
+		return
+	}
+
+	required, err := h.isTwoFactorRequired(ctx.Request.Context(), user.Type)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if required {
+		h.respondMFAChallenge(ctx, user, mfaChallengeEnroll, utils.ScopeMFAEnroll, mfaEnrollTokenTTL)
+		return
+	}
+
+	h.respondAuth(ctx, user, status)
+}
+
+func (h *handlerV1) respondAuth(ctx *gin.Context, user *repo.User, status int) {
+	token, refreshToken, err := h.createSession(ctx, user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(status, models.AuthResponse{
+		ID:           user.ID,
+		FirstName:    user.FirstName,
+		LastName:     user.LastName,
+		DateOfBirth:  user.DateOfBirth,
+		Email:        user.Email,
+		Type:         user.Type,
+		CreatedAt:    user.CreatedAt,
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+	})
+}
+
+func (h *handlerV1) respondMFAChallenge(ctx *gin.Context, user *repo.User, challenge, scope string, ttl time.Duration) {
+	token, payload, err := utils.CreateToken(h.keys, &utils.TokenParams{
+		UserID:   user.ID,
+		UserType: user.Type,
+		Email:    user.Email,
+		Scope:    scope,
+		Duration: ttl,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, models.MFAChallengeResponse{
+		Type:      challenge,
+		MFAToken:  token,
+		ExpiresAt: payload.ExpiredAt,
+	})
+}
+
+func (h *handlerV1) isTwoFactorEnabled(ctx context.Context, userID int64) (bool, error) {
+	tf, err := h.storage.TwoFactor().Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return tf.EnabledAt != nil, nil
+}
+
+// isTwoFactorRequired reports whether users of the type must use two-factor
+// authentication
+func (h *handlerV1) isTwoFactorRequired(ctx context.Context, userType string) (bool, error) {
+	if userType != repo.UserTypeSuperAdmin && userType != repo.UserTypeOwner {
+		return false, nil
+	}
+
+	return h.isTwoFactorPolicyOn(ctx)
+}
+
+func (h *handlerV1) isTwoFactorPolicyOn(ctx context.Context) (bool, error) {
+	value, err := h.storage.Setting().Get(ctx, repo.SettingTwoFactorRequired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return value == twoFactorRequiredOn, nil
+}
+
+// checkTwoFactorCode accepts either a TOTP code, which cannot be used twice,
+// or an unused recovery code
+func (h *handlerV1) checkTwoFactorCode(ctx context.Context, tf *repo.TwoFactor, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if isTOTPCode(code) {
+		step, ok := totp.Validate(tf.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		err := h.storage.TwoFactor().UseStep(ctx, tf.UserID, step)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	err := h.storage.TwoFactor().UseRecoveryCode(ctx, tf.UserID, hashRecoveryCode(code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != totp.Digits {
+		return false
+	}
+
+	_, err := strconv.ParseUint(code, 10, 64)
+	return err == nil
+}
+
+// newRecoveryCode returns a random code formatted as xxxx-xxxx-xxxx-xxxx
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	s := strings.ToLower(base32.StdEncoding.EncodeToString(b))
+
+	return s[0:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:16], nil
+}
+
+// hashRecoveryCode hashes the code ignoring case and separators
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/2fa [get]
+// @Summary Get two-factor authentication status
+// @Description Get whether two-factor authentication is enabled for, and required of, the current user
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TwoFactorStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetTwoFactorStatus(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	enabled, err := h.isTwoFactorEnabled(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	required, err := h.isTwoFactorRequired(ctx.Request.Context(), payload.UserType)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TwoFactorStatus{
+		Enabled:  enabled,
+		Required: required,
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/2fa/enroll [post]
+// @Summary Enroll in two-factor authentication
+// @Description Create a TOTP secret and recovery codes. Two-factor authentication is enabled once a code of the secret is confirmed. Enrolling again before confirming replaces the secret and the codes.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.TwoFactorEnrollResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) EnrollTwoFactor(ctx *gin.Context) {
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([]string, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	err = h.storage.TwoFactor().Enroll(ctx.Request.Context(), &repo.EnrollTwoFactor{
+		UserID:             payload.UserID,
+		Secret:             secret,
+		RecoveryCodeHashes: hashes,
+	})
+	if err != nil {
+		if errors.Is(err, repo.ErrTwoFactorEnabled) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, models.TwoFactorEnrollResponse{
+		Secret:        secret,
+		OTPAuthURI:    totp.URI(totpIssuer, payload.Email, secret),
+		RecoveryCodes: codes,
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/2fa/confirm [post]
+// @Summary Confirm two-factor authentication
+// @Description Enable two-factor authentication by confirming a code of the enrolled secret
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param data body models.TwoFactorCodeRequest true "Data"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) ConfirmTwoFactor(ctx *gin.Context) {
+
+	var req models.TwoFactorCodeRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	userKey := strconv.FormatInt(payload.UserID, 10)
+
+	if !h.checkLockout(ctx, h.limiters.user, userKey) {
+		return
+	}
+
+	tf, err := h.storage.TwoFactor().Get(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if tf.EnabledAt != nil {
+		ctx.JSON(http.StatusConflict, errorResponse(repo.ErrTwoFactorEnabled))
+		return
+	}
+
+	step, ok := totp.Validate(tf.Secret, strings.TrimSpace(req.Code), time.Now())
+	if !ok {
+		h.failAttempt(ctx, h.limiters.user, userKey, http.StatusBadRequest, ErrInvalidMFACode)
+		return
+	}
+
+	err = h.limiters.user.Reset(ctx.Request.Context(), userKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.storage.TwoFactor().Enable(ctx.Request.Context(), payload.UserID, step)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusConflict, errorResponse(repo.ErrTwoFactorEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "Two-factor authentication has been enabled",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/2fa/disable [post]
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication with a TOTP or recovery code. Not allowed if two-factor authentication is required for the account.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param data body models.TwoFactorCodeRequest true "Data"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) DisableTwoFactor(ctx *gin.Context) {
+
+	var req models.TwoFactorCodeRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, err := h.GetAuthPayload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	required, err := h.isTwoFactorRequired(ctx.Request.Context(), payload.UserType)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if required {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrTwoFactorRequired))
+		return
+	}
+
+	userKey := strconv.FormatInt(payload.UserID, 10)
+
+	if !h.checkLockout(ctx, h.limiters.user, userKey) {
+		return
+	}
+
+	tf, err := h.storage.TwoFactor().Get(ctx.Request.Context(), payload.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if tf == nil || tf.EnabledAt == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrTwoFactorDisabled))
+		return
+	}
+
+	ok, err := h.checkTwoFactorCode(ctx.Request.Context(), tf, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !ok {
+		h.failAttempt(ctx, h.limiters.user, userKey, http.StatusBadRequest, ErrInvalidMFACode)
+		return
+	}
+
+	err = h.limiters.user.Reset(ctx.Request.Context(), userKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.storage.TwoFactor().Disable(ctx.Request.Context(), payload.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "Two-factor authentication has been disabled",
+	})
+}
+
+// @Router /auth/2fa/verify [post]
+// @Summary Verify two-factor authentication
+// @Description Complete a login by exchanging the mfa_token returned by it and a TOTP or recovery code for an access token
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param data body models.TwoFactorVerifyRequest true "Data"
+// @Success 201 {object} models.AuthResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 429 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) VerifyTwoFactor(ctx *gin.Context) {
+
+	var req models.TwoFactorVerifyRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	payload, status, err := h.verifyToken(ctx.Request.Context(), req.MFAToken)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err))
+		return
+	}
+
+	if payload.Scope != utils.ScopeMFAPending {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ErrTokenScope))
+		return
+	}
+
+	userKey := strconv.FormatInt(payload.UserID, 10)
+
+	if !h.checkLockout(ctx, h.limiters.user, userKey) {
+		return
+	}
+
+	user, err := h.storage.User().Get(ctx.Request.Context(), payload.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(utils.ErrInvalidToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.BannedAt != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrUserBanned))
+		return
+	}
+
+	tf, err := h.storage.TwoFactor().Get(ctx.Request.Context(), user.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Disabled since the login, e.g. from another session
+	if tf == nil || tf.EnabledAt == nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ErrTwoFactorDisabled))
+		return
+	}
+
+	ok, err := h.checkTwoFactorCode(ctx.Request.Context(), tf, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if !ok {
+		h.failAttempt(ctx, h.limiters.user, userKey, http.StatusUnauthorized, ErrInvalidMFACode)
+		return
+	}
+
+	err = h.limiters.user.Reset(ctx.Request.Context(), userKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = h.revokeToken(ctx.Request.Context(), payload)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	h.respondAuth(ctx, user, http.StatusCreated)
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/2fa/policy [get]
+// @Summary Get two-factor authentication policy
+// @Description Get whether superadmins and owners must use two-factor authentication
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.TwoFactorPolicy
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) GetTwoFactorPolicy(ctx *gin.Context) {
+	required, err := h.isTwoFactorPolicyOn(ctx.Request.Context())
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TwoFactorPolicy{
+		Required: required,
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /auth/2fa/policy [put]
+// @Summary Update two-factor authentication policy
+// @Description Require two-factor authentication of all superadmins and owners. Those who have not enabled it are asked to enroll at their next login.
+// @Tags two-factor
+// @Accept json
+// @Produce json
+// @Param data body models.UpdateTwoFactorPolicyRequest true "Data"
+// @Success 200 {object} models.TwoFactorPolicy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) UpdateTwoFactorPolicy(ctx *gin.Context) {
+
+	var req models.UpdateTwoFactorPolicyRequest
+
+	err := ctx.ShouldBindJSON(&req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	err = h.storage.Setting().Set(ctx.Request.Context(), repo.SettingTwoFactorRequired, strconv.FormatBool(*req.Required))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.TwoFactorPolicy{
+		Required: *req.Required,
+	})
+}
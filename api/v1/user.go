@@ -8,6 +8,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/ibrat-muslim/booking-service/api/models"
+	"github.com/ibrat-muslim/booking-service/pkg/policy"
 	"github.com/ibrat-muslim/booking-service/pkg/utils"
 	"github.com/ibrat-muslim/booking-service/storage/repo"
 )
@@ -22,22 +23,12 @@ import (
 // @Param user body models.CreateUserRequest true "User"
 // @Success 201 {object} models.User
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) CreateUser(ctx *gin.Context) {
-	payload, err := h.GetAuthPayload(ctx)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
-	}
-
-	if payload.UserType != repo.UserTypeSuperAdmin {
-		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
-		return
-	}
-
 	var req models.CreateUserRequest
 
-	err = ctx.ShouldBindJSON(&req)
+	err := ctx.ShouldBindJSON(&req)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
@@ -49,7 +40,7 @@ func (h *handlerV1) CreateUser(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.User().Create(&repo.User{
+	resp, err := h.storage.User().Create(ctx.Request.Context(), &repo.User{
 		FirstName:       req.FirstName,
 		LastName:        req.LastName,
 		DateOfBirth:     req.DateOfBirth,
@@ -88,7 +79,7 @@ func (h *handlerV1) GetUser(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.User().Get(id)
+	resp, err := h.storage.User().Get(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -117,7 +108,7 @@ func (h *handlerV1) GetUserProfile(ctx *gin.Context) {
 		return
 	}
 
-	resp, err := h.storage.User().Get(payload.UserID)
+	resp, err := h.storage.User().Get(ctx.Request.Context(), payload.UserID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -143,7 +134,7 @@ func (h *handlerV1) GetUsers(ctx *gin.Context) {
 		return
 	}
 
-	result, err := h.storage.User().GetAll(&repo.GetUsersParams{
+	result, err := h.storage.User().GetAll(ctx.Request.Context(), &repo.GetUsersParams{
 		Limit:  request.Limit,
 		Page:   request.Page,
 		Search: request.Search,
@@ -181,6 +172,7 @@ func getUsersResponse(data *repo.GetUsersResult) *models.GetUsersResponse {
 // @Param user body models.CreateUserRequest true "User"
 // @Success 200 {object} models.OKResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) UpdateUser(ctx *gin.Context) {
@@ -198,13 +190,31 @@ func (h *handlerV1) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	if !h.authorize(ctx, policy.UserUpdate, id) {
+		return
+	}
+
+	user, err := h.storage.User().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if req.Type != user.Type && !h.authorize(ctx, policy.UserChangeType, id) {
+		return
+	}
+
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	err = h.storage.User().Update(&repo.User{
+	err = h.storage.User().Update(ctx.Request.Context(), &repo.User{
 		ID:              id,
 		FirstName:       req.FirstName,
 		LastName:        req.LastName,
@@ -226,6 +236,16 @@ func (h *handlerV1) UpdateUser(ctx *gin.Context) {
 		return
 	}
 
+	// Tokens carry the type of the user and were obtained with the password,
+	// so they must not outlive a change of either
+	if req.Type != user.Type || utils.CheckPassword(req.Password, user.Password) != nil {
+		err = h.revokeUserTokens(ctx.Request.Context(), id)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, models.OKResponse{
 		Message: "successfully updated",
 	})
@@ -241,6 +261,7 @@ func (h *handlerV1) UpdateUser(ctx *gin.Context) {
 // @Param id path int true "ID"
 // @Success 200 {object} models.OKResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 func (h *handlerV1) DeleteUser(ctx *gin.Context) {
@@ -250,7 +271,11 @@ func (h *handlerV1) DeleteUser(ctx *gin.Context) {
 		return
 	}
 
-	err = h.storage.User().Delete(id)
+	if !h.authorize(ctx, policy.UserDelete, id) {
+		return
+	}
+
+	err = h.storage.User().Delete(ctx.Request.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			ctx.JSON(http.StatusNotFound, errorResponse(err))
@@ -265,6 +290,95 @@ func (h *handlerV1) DeleteUser(ctx *gin.Context) {
 	})
 }
 
+// @Security ApiKeyAuth
+// @Router /users/{id}/ban [post]
+// @Summary Ban a user
+// @Description Ban a user. Every token and session of the user is revoked and the user can no longer log in.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) BanUser(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := h.storage.User().Get(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if user.Type == repo.UserTypeSuperAdmin {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrForbidden))
+		return
+	}
+
+	if user.BannedAt == nil {
+		err = h.storage.User().Ban(ctx.Request.Context(), id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	err = h.revokeUserTokens(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully banned",
+	})
+}
+
+// @Security ApiKeyAuth
+// @Router /users/{id}/ban [delete]
+// @Summary Unban a user
+// @Description Unban a user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path int true "ID"
+// @Success 200 {object} models.OKResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+func (h *handlerV1) UnbanUser(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	err = h.storage.User().Unban(ctx.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, models.OKResponse{
+		Message: "successfully unbanned",
+	})
+}
+
 func parseUserToModel(user *repo.User) models.User {
 	return models.User{
 		ID:              user.ID,
@@ -278,5 +392,6 @@ func parseUserToModel(user *repo.User) models.User {
 		Address:         user.Address,
 		Type:            user.Type,
 		CreatedAt:       user.CreatedAt,
+		BannedAt:        user.BannedAt,
 	}
 }
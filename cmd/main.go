@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 
@@ -10,6 +11,9 @@ import (
 
 	"github.com/ibrat-muslim/booking-service/api"
 	"github.com/ibrat-muslim/booking-service/config"
+	"github.com/ibrat-muslim/booking-service/pkg/keyring"
+	"github.com/ibrat-muslim/booking-service/pkg/payments"
+	"github.com/ibrat-muslim/booking-service/pkg/ratelimit"
 	"github.com/ibrat-muslim/booking-service/storage"
 )
 
@@ -29,18 +33,41 @@ func main() {
 		log.Fatalf("failed to connect database: %v", err)
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr: cfg.Redis.Addr,
-	})
+	strg := storage.NewStoragePg(psqlConn, cfg.Postgres.QueryTimeout)
+
+	var inMemory storage.InMemoryStorageI
 
-	strg := storage.NewStoragePg(psqlConn)
+	switch cfg.InMemory.Backend {
+	case "redis":
+		rdb := redis.NewClient(&redis.Options{
+			Addr: cfg.Redis.Addr,
+		})
+		inMemory = storage.NewInMemoryStorage(rdb, cfg.Redis.Timeout)
+	case "local":
+		inMemory = storage.NewLocalInMemoryStorage(context.Background(), cfg.InMemory.JanitorInterval)
+	default:
+		log.Fatalf("unknown in-memory backend %q", cfg.InMemory.Backend)
+	}
 
-	inMemory := storage.NewInMemoryStorage(rdb)
+	paymentProvider := payments.NewFakeProvider(cfg.Payment.WebhookSecret)
+
+	keys, err := keyring.Load(cfg.AuthSecretKey, cfg.AuthKeys, cfg.AuthSigningKey)
+	if err != nil {
+		log.Fatalf("failed to load auth keys: %v", err)
+	}
+
+	rateLimits, err := ratelimit.LoadRules(cfg.RateLimit.Default, cfg.RateLimit.Routes)
+	if err != nil {
+		log.Fatalf("failed to load rate limits: %v", err)
+	}
 
 	apiServer := api.New(&api.RouterOptions{
-		Cfg:      &cfg,
-		Storage:  strg,
-		InMemory: inMemory,
+		Cfg:        &cfg,
+		Storage:    strg,
+		InMemory:   inMemory,
+		Payments:   paymentProvider,
+		Keys:       keys,
+		RateLimits: rateLimits,
 	})
 
 	err = apiServer.Run(cfg.HttpPort)
@@ -2,17 +2,29 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	HttpPort      string
-	Postgres      PostgresConfig
-	Smtp          Smtp
-	Redis         Redis
-	AuthSecretKey string
+	HttpPort        string
+	Postgres        PostgresConfig
+	Smtp            Smtp
+	Redis           Redis
+	InMemory        InMemory
+	Payment         Payment
+	RateLimit       RateLimit
+	OIDC            OIDC
+	AuthSecretKey   string
+	AuthKeys        string
+	AuthSigningKey  string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	// MagicLinkURL is the page login links point to, with the token added
+	// as the token query parameter
+	MagicLinkURL string
 }
 
 type PostgresConfig struct {
@@ -21,6 +33,8 @@ type PostgresConfig struct {
 	User     string
 	Password string
 	Database string
+	// QueryTimeout cancels a single repository call, 0 disables it
+	QueryTimeout time.Duration
 }
 
 type Smtp struct {
@@ -30,6 +44,41 @@ type Smtp struct {
 
 type Redis struct {
 	Addr string
+	// Timeout cancels a single command, 0 disables it
+	Timeout time.Duration
+}
+
+// InMemory selects where codes, tokens and counters are kept
+type InMemory struct {
+	// Backend is "redis", or "local" to keep them in process and run
+	// without Redis
+	Backend string
+	// JanitorInterval is how often the local backend removes expired keys
+	JanitorInterval time.Duration
+}
+
+type Payment struct {
+	WebhookSecret string
+}
+
+// OIDC is the OpenID Connect provider users may sign in with. Signing in
+// with a provider is disabled if Issuer is empty.
+type OIDC struct {
+	// Provider names the provider in the identities of users
+	Provider     string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+type RateLimit struct {
+	// Default is the limit shared by routes without one of their own, such
+	// as "300/1m"
+	Default string
+	// Routes is a comma separated list of route limits, such as
+	// "POST /v1/auth/login=10/1m"
+	Routes string
 }
 
 func Load(path string) Config {
@@ -41,23 +90,64 @@ func Load(path string) Config {
 	conf := viper.New()
 	conf.AutomaticEnv()
 
+	conf.SetDefault("ACCESS_TOKEN_TTL", 15*time.Minute)
+	conf.SetDefault("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	conf.SetDefault("OIDC_PROVIDER", "oidc")
+	conf.SetDefault("POSTGRES_QUERY_TIMEOUT", 5*time.Second)
+	conf.SetDefault("REDIS_TIMEOUT", time.Second)
+	conf.SetDefault("IN_MEMORY_BACKEND", "redis")
+	conf.SetDefault("IN_MEMORY_JANITOR_INTERVAL", time.Minute)
+	conf.SetDefault("RATE_LIMIT_DEFAULT", "300/1m")
+	conf.SetDefault("RATE_LIMIT_ROUTES", "POST /v1/auth/register=5/1m,"+
+		"POST /v1/auth/verify=10/1m,"+
+		"POST /v1/auth/login=10/1m,"+
+		"POST /v1/auth/forgot-password=5/1m,"+
+		"POST /v1/auth/verify-forgot-password=10/1m,"+
+		"POST /v1/auth/2fa/verify=10/1m,"+
+		"POST /v1/auth/magic-link=5/1m")
+
 	cfg := Config{
 		HttpPort: conf.GetString("HTTP_PORT"),
 		Postgres: PostgresConfig{
-			Host:     conf.GetString("POSTGRES_HOST"),
-			Port:     conf.GetString("POSTGRES_PORT"),
-			User:     conf.GetString("POSTGRES_USER"),
-			Password: conf.GetString("POSTGRES_PASSWORD"),
-			Database: conf.GetString("POSTGRES_DATABASE"),
+			Host:         conf.GetString("POSTGRES_HOST"),
+			Port:         conf.GetString("POSTGRES_PORT"),
+			User:         conf.GetString("POSTGRES_USER"),
+			Password:     conf.GetString("POSTGRES_PASSWORD"),
+			Database:     conf.GetString("POSTGRES_DATABASE"),
+			QueryTimeout: conf.GetDuration("POSTGRES_QUERY_TIMEOUT"),
 		},
 		Smtp: Smtp{
 			Sender:   conf.GetString("SMTP_SENDER"),
 			Password: conf.GetString("SMTP_PASSWORD"),
 		},
 		Redis: Redis{
-			Addr: conf.GetString("REDIS_ADDR"),
+			Addr:    conf.GetString("REDIS_ADDR"),
+			Timeout: conf.GetDuration("REDIS_TIMEOUT"),
+		},
+		InMemory: InMemory{
+			Backend:         conf.GetString("IN_MEMORY_BACKEND"),
+			JanitorInterval: conf.GetDuration("IN_MEMORY_JANITOR_INTERVAL"),
+		},
+		Payment: Payment{
+			WebhookSecret: conf.GetString("PAYMENT_WEBHOOK_SECRET"),
+		},
+		OIDC: OIDC{
+			Provider:     conf.GetString("OIDC_PROVIDER"),
+			Issuer:       conf.GetString("OIDC_ISSUER"),
+			ClientID:     conf.GetString("OIDC_CLIENT_ID"),
+			ClientSecret: conf.GetString("OIDC_CLIENT_SECRET"),
+			RedirectURL:  conf.GetString("OIDC_REDIRECT_URL"),
+		},
+		RateLimit: RateLimit{
+			Default: conf.GetString("RATE_LIMIT_DEFAULT"),
+			Routes:  conf.GetString("RATE_LIMIT_ROUTES"),
 		},
-		AuthSecretKey: conf.GetString("AUTH_SECRET_KEY"),
+		AuthSecretKey:   conf.GetString("AUTH_SECRET_KEY"),
+		AuthKeys:        conf.GetString("AUTH_KEYS"),
+		AuthSigningKey:  conf.GetString("AUTH_SIGNING_KEY"),
+		AccessTokenTTL:  conf.GetDuration("ACCESS_TOKEN_TTL"),
+		RefreshTokenTTL: conf.GetDuration("REFRESH_TOKEN_TTL"),
+		MagicLinkURL:    conf.GetString("MAGIC_LINK_URL"),
 	}
 
 	return cfg
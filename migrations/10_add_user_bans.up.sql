@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMP WITH TIME ZONE;
\ No newline at end of file
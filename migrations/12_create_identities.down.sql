@@ -0,0 +1,4 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_gender_check;
+ALTER TABLE users ADD CONSTRAINT users_gender_check CHECK (gender IN('male', 'female'));
+
+DROP TABLE IF EXISTS identities;
\ No newline at end of file
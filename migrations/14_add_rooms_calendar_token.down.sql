@@ -0,0 +1 @@
+ALTER TABLE rooms DROP COLUMN IF EXISTS calendar_token;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS calendar_token VARCHAR(64) UNIQUE;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rooms;
+
+DROP TABLE IF EXISTS properties;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS properties(
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    address VARCHAR NOT NULL,
+    city VARCHAR(50) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL CHECK (latitude BETWEEN -90 AND 90),
+    longitude DOUBLE PRECISION NOT NULL CHECK (longitude BETWEEN -180 AND 180),
+    amenities TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS properties_owner_id_idx ON properties(owner_id);
+CREATE INDEX IF NOT EXISTS properties_city_idx ON properties(lower(city));
+
+CREATE TABLE IF NOT EXISTS rooms(
+    id SERIAL PRIMARY KEY,
+    property_id INTEGER NOT NULL REFERENCES properties(id) ON DELETE CASCADE,
+    title VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    base_price NUMERIC(12, 2) NOT NULL CHECK (base_price >= 0),
+    photos TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS rooms_property_id_idx ON rooms(property_id);
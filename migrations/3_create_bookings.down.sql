@@ -0,0 +1 @@
+DROP TABLE IF EXISTS bookings;
\ No newline at end of file
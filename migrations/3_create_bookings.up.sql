@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS bookings(
+    id SERIAL PRIMARY KEY,
+    room_id INTEGER NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    guest_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    check_in DATE NOT NULL,
+    check_out DATE NOT NULL,
+    guests_count INTEGER NOT NULL CHECK (guests_count > 0),
+    total_price NUMERIC(12, 2) NOT NULL CHECK (total_price >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN('pending', 'confirmed', 'checked_in', 'completed', 'cancelled', 'no_show')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    CHECK (check_out > check_in),
+    CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+        room_id WITH =,
+        daterange(check_in, check_out) WITH &&
+    ) WHERE (status IN('pending', 'confirmed', 'checked_in', 'completed'))
+);
+
+CREATE INDEX IF NOT EXISTS bookings_guest_id_idx ON bookings(guest_id);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS pricing_rules(
+    id SERIAL PRIMARY KEY,
+    room_id INTEGER NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN('season', 'weekday', 'min_nights', 'length_of_stay')),
+    start_date DATE,
+    end_date DATE,
+    days_of_week INTEGER[],
+    price NUMERIC(12, 2) CHECK (price >= 0),
+    multiplier NUMERIC(6, 3) CHECK (multiplier > 0),
+    min_nights INTEGER CHECK (min_nights > 0),
+    discount_percent NUMERIC(5, 2) CHECK (discount_percent BETWEEN 0 AND 100),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date >= start_date),
+    CHECK (type <> 'season' OR (start_date IS NOT NULL AND end_date IS NOT NULL AND price IS NOT NULL)),
+    CHECK (type <> 'weekday' OR (days_of_week IS NOT NULL AND multiplier IS NOT NULL)),
+    CHECK (type <> 'min_nights' OR min_nights IS NOT NULL),
+    CHECK (type <> 'length_of_stay' OR (min_nights IS NOT NULL AND discount_percent IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS pricing_rules_room_id_idx ON pricing_rules(room_id);
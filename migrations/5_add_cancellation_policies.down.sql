@@ -0,0 +1,8 @@
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS refund_amount;
+
+ALTER TABLE properties
+    DROP CONSTRAINT IF EXISTS properties_custom_cancellation_tiers,
+    DROP COLUMN IF EXISTS cancellation_tiers,
+    DROP COLUMN IF EXISTS cancellation_policy;
\ No newline at end of file
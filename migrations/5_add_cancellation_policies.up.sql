@@ -0,0 +1,10 @@
+ALTER TABLE properties
+    ADD COLUMN IF NOT EXISTS cancellation_policy VARCHAR(10) NOT NULL DEFAULT 'moderate'
+        CHECK (cancellation_policy IN('flexible', 'moderate', 'strict', 'custom')),
+    ADD COLUMN IF NOT EXISTS cancellation_tiers JSONB,
+    ADD CONSTRAINT properties_custom_cancellation_tiers
+        CHECK (cancellation_policy <> 'custom' OR cancellation_tiers IS NOT NULL);
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS refund_amount NUMERIC(12, 2) CHECK (refund_amount >= 0),
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP WITH TIME ZONE;
\ No newline at end of file
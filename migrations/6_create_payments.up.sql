@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS payments(
+    id SERIAL PRIMARY KEY,
+    booking_id INTEGER NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    provider_payment_id VARCHAR(100) UNIQUE,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    refunded_amount NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN('pending', 'authorized', 'captured', 'refunded', 'failed')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    CHECK (refunded_amount <= amount),
+    CHECK (provider_payment_id IS NOT NULL OR status IN('pending', 'failed'))
+);
+
+CREATE INDEX IF NOT EXISTS payments_booking_id_idx ON payments(booking_id);
+
+-- A booking is paid by at most one payment at a time
+CREATE UNIQUE INDEX IF NOT EXISTS payments_booking_id_active_idx ON payments(booking_id)
+    WHERE status IN('pending', 'authorized', 'captured');
+
+CREATE TABLE IF NOT EXISTS payment_events(
+    id VARCHAR(100) PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    provider_payment_id VARCHAR(100) NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS room_blocks(
+    id SERIAL PRIMARY KEY,
+    room_id INTEGER NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    source VARCHAR(10) NOT NULL DEFAULT 'manual' CHECK (source IN('manual', 'ical')),
+    uid VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    CHECK (end_date > start_date),
+    CHECK (source <> 'ical' OR uid IS NOT NULL),
+    UNIQUE (room_id, uid)
+);
+
+CREATE INDEX IF NOT EXISTS room_blocks_room_id_idx ON room_blocks(room_id);
\ No newline at end of file
@@ -0,0 +1,114 @@
+package cancellation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cancellation policies a property can have
+const (
+	PolicyFlexible = "flexible"
+	PolicyModerate = "moderate"
+	PolicyStrict   = "strict"
+	PolicyCustom   = "custom"
+)
+
+var (
+	ErrUnknownPolicy = errors.New("unknown cancellation policy")
+	ErrNoTiers       = errors.New("custom cancellation policy requires at least one tier")
+)
+
+// Tier grants RefundPercent of the booking total when the booking is cancelled
+// at least DaysBefore days before check-in
+type Tier struct {
+	DaysBefore    int
+	RefundPercent float64
+}
+
+var policyTiers = map[string][]Tier{
+	PolicyFlexible: {
+		{DaysBefore: 1, RefundPercent: 100},
+	},
+	PolicyModerate: {
+		{DaysBefore: 5, RefundPercent: 100},
+		{DaysBefore: 1, RefundPercent: 50},
+	},
+	PolicyStrict: {
+		{DaysBefore: 14, RefundPercent: 100},
+		{DaysBefore: 7, RefundPercent: 50},
+	},
+}
+
+// Tiers returns the tiers of the policy ordered from the earliest cancellation
+// to the latest. custom is only used for the custom policy.
+func Tiers(policy string, custom []Tier) ([]Tier, error) {
+	var tiers []Tier
+
+	switch policy {
+	case PolicyFlexible, PolicyModerate, PolicyStrict:
+		tiers = policyTiers[policy]
+	case PolicyCustom:
+		if len(custom) == 0 {
+			return nil, ErrNoTiers
+		}
+		tiers = custom
+	default:
+		return nil, ErrUnknownPolicy
+	}
+
+	result := make([]Tier, len(tiers))
+	copy(result, tiers)
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DaysBefore > result[j].DaysBefore
+	})
+
+	return result, nil
+}
+
+// Refund returns the refunded percent and amount of total for a booking
+// with the given check-in date cancelled at cancelledAt
+func Refund(tiers []Tier, total float64, checkIn, cancelledAt time.Time) (float64, float64) {
+	if !cancelledAt.Before(checkIn) {
+		return 0, 0
+	}
+
+	daysBefore := int(checkIn.Sub(cancelledAt).Hours() / 24)
+
+	for _, tier := range tiers {
+		if daysBefore >= tier.DaysBefore {
+			return tier.RefundPercent, math.Round(total*tier.RefundPercent) / 100
+		}
+	}
+
+	return 0, 0
+}
+
+// Describe returns a human readable text of the tiers
+func Describe(tiers []Tier) string {
+	parts := make([]string, 0, len(tiers)+1)
+
+	for _, tier := range tiers {
+		parts = append(parts, fmt.Sprintf("%s%% refund if cancelled at least %d %s before check-in",
+			formatPercent(tier.RefundPercent), tier.DaysBefore, pluralDays(tier.DaysBefore)))
+	}
+
+	parts = append(parts, "no refund afterwards")
+
+	return strings.Join(parts, ", ") + "."
+}
+
+func formatPercent(percent float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", percent), "0"), ".")
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "day"
+	}
+	return "days"
+}
@@ -0,0 +1,120 @@
+package cancellation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiers(t *testing.T) {
+	tiers, err := Tiers(PolicyCustom, []Tier{
+		{DaysBefore: 2, RefundPercent: 25},
+		{DaysBefore: 30, RefundPercent: 100},
+		{DaysBefore: 10, RefundPercent: 60},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Tier{
+		{DaysBefore: 30, RefundPercent: 100},
+		{DaysBefore: 10, RefundPercent: 60},
+		{DaysBefore: 2, RefundPercent: 25},
+	}, tiers)
+
+	_, err = Tiers(PolicyCustom, nil)
+	require.ErrorIs(t, err, ErrNoTiers)
+
+	_, err = Tiers("lenient", nil)
+	require.ErrorIs(t, err, ErrUnknownPolicy)
+
+	tiers, err = Tiers(PolicyStrict, []Tier{{DaysBefore: 1, RefundPercent: 100}})
+	require.NoError(t, err)
+	require.Equal(t, policyTiers[PolicyStrict], tiers)
+}
+
+func TestRefund(t *testing.T) {
+	checkIn := time.Date(2024, time.July, 20, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name        string
+		policy      string
+		cancelledAt time.Time
+		percent     float64
+		amount      float64
+	}{
+		{
+			name:        "flexible a day before",
+			policy:      PolicyFlexible,
+			cancelledAt: checkIn.Add(-24 * time.Hour),
+			percent:     100,
+			amount:      333.33,
+		},
+		{
+			name:        "flexible on the day",
+			policy:      PolicyFlexible,
+			cancelledAt: checkIn.Add(-23 * time.Hour),
+			percent:     0,
+			amount:      0,
+		},
+		{
+			name:        "moderate five days before",
+			policy:      PolicyModerate,
+			cancelledAt: checkIn.AddDate(0, 0, -5),
+			percent:     100,
+			amount:      333.33,
+		},
+		{
+			name:        "moderate three days before",
+			policy:      PolicyModerate,
+			cancelledAt: checkIn.AddDate(0, 0, -3),
+			percent:     50,
+			amount:      166.67,
+		},
+		{
+			name:        "strict ten days before",
+			policy:      PolicyStrict,
+			cancelledAt: checkIn.AddDate(0, 0, -10),
+			percent:     50,
+			amount:      166.67,
+		},
+		{
+			name:        "strict three days before",
+			policy:      PolicyStrict,
+			cancelledAt: checkIn.AddDate(0, 0, -3),
+			percent:     0,
+			amount:      0,
+		},
+		{
+			name:        "after check-in",
+			policy:      PolicyFlexible,
+			cancelledAt: checkIn.Add(time.Hour),
+			percent:     0,
+			amount:      0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tiers, err := Tiers(tc.policy, nil)
+			require.NoError(t, err)
+
+			percent, amount := Refund(tiers, 333.33, checkIn, tc.cancelledAt)
+			require.Equal(t, tc.percent, percent)
+			require.Equal(t, tc.amount, amount)
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tiers, err := Tiers(PolicyModerate, nil)
+	require.NoError(t, err)
+	require.Equal(t,
+		"100% refund if cancelled at least 5 days before check-in, "+
+			"50% refund if cancelled at least 1 day before check-in, no refund afterwards.",
+		Describe(tiers),
+	)
+
+	require.Equal(t,
+		"12.5% refund if cancelled at least 0 days before check-in, no refund afterwards.",
+		Describe([]Tier{{DaysBefore: 0, RefundPercent: 12.5}}),
+	)
+}
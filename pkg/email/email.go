@@ -19,6 +19,7 @@ type SendEmailRequest struct {
 const (
 	VerificationEmail   = "verification_email"
 	ForgotPasswordEmail = "forgot_password_email"
+	MagicLinkEmail      = "magic_link_email"
 )
 
 func SendEmail(cfg *config.Config, req *SendEmailRequest) error {
@@ -56,6 +57,8 @@ func getTemplatePath(emailType string) string {
 		return "./templates/verification_email.html"
 	case ForgotPasswordEmail:
 		return "./templates/forgot_password_email.html"
+	case MagicLinkEmail:
+		return "./templates/magic_link_email.html"
 	}
 
 	return ""
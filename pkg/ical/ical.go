@@ -0,0 +1,303 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405"
+
+	// maxLineLength is the maximum length of a content line in octets, excluding CRLF
+	maxLineLength = 75
+
+	StatusCancelled = "CANCELLED"
+)
+
+var (
+	ErrNoCalendar = errors.New("ical: no VCALENDAR found")
+	ErrMissingUID = errors.New("ical: event without UID")
+	ErrNoStart    = errors.New("ical: event without DTSTART")
+)
+
+// Event is an all-day event of an iCalendar (RFC 5545) feed. End is exclusive, as DTEND is.
+type Event struct {
+	UID     string
+	Start   time.Time
+	End     time.Time
+	Summary string
+	Status  string
+}
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []*Event
+}
+
+// Encode writes the calendar to w. Every event is stamped with now.
+func Encode(w io.Writer, calendar *Calendar, now time.Time) error {
+	bw := bufio.NewWriter(w)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + escapeText(calendar.ProdID),
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+
+	if calendar.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escapeText(calendar.Name))
+	}
+
+	stamp := now.UTC().Format(dateTimeLayout) + "Z"
+
+	for _, event := range calendar.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+escapeText(event.UID),
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+event.Start.Format(dateLayout),
+			"DTEND;VALUE=DATE:"+event.End.Format(dateLayout),
+		)
+
+		if event.Summary != "" {
+			lines = append(lines, "SUMMARY:"+escapeText(event.Summary))
+		}
+
+		if event.Status != "" {
+			lines = append(lines, "STATUS:"+event.Status)
+		}
+
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		_, err := bw.WriteString(fold(line))
+		if err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Parse reads the events of the first calendar in r. Date-time values are
+// truncated to their date, and an event without DTEND lasts one day.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		calendar *Calendar
+		event    *Event
+		hasEnd   bool
+		depth    int
+	)
+
+	for _, line := range lines {
+		name, params, value := splitLine(line)
+
+		switch {
+		case name == "BEGIN" && value == "VCALENDAR" && calendar == nil:
+			calendar = &Calendar{
+				Events: make([]*Event, 0),
+			}
+		case calendar == nil:
+			continue
+		case name == "BEGIN" && value == "VEVENT" && event == nil:
+			event = &Event{}
+			hasEnd = false
+		case name == "BEGIN":
+			// Nested components such as VALARM are skipped
+			depth++
+		case name == "END" && depth > 0:
+			depth--
+		case depth > 0:
+			continue
+		case name == "END" && value == "VEVENT" && event != nil:
+			if event.UID == "" {
+				return nil, ErrMissingUID
+			}
+			if event.Start.IsZero() {
+				return nil, ErrNoStart
+			}
+			if !hasEnd || !event.End.After(event.Start) {
+				event.End = event.Start.AddDate(0, 0, 1)
+			}
+			calendar.Events = append(calendar.Events, event)
+			event = nil
+		case name == "END" && value == "VCALENDAR":
+			return calendar, nil
+		case event != nil:
+			err := parseEventProperty(event, name, params, value)
+			if err != nil {
+				return nil, err
+			}
+			if name == "DTEND" {
+				hasEnd = true
+			}
+		case name == "X-WR-CALNAME":
+			calendar.Name = unescapeText(value)
+		case name == "PRODID":
+			calendar.ProdID = unescapeText(value)
+		}
+	}
+
+	if calendar == nil {
+		return nil, ErrNoCalendar
+	}
+
+	return calendar, nil
+}
+
+func parseEventProperty(event *Event, name string, params map[string]string, value string) error {
+	var err error
+
+	switch name {
+	case "UID":
+		event.UID = unescapeText(value)
+	case "SUMMARY":
+		event.Summary = unescapeText(value)
+	case "STATUS":
+		event.Status = strings.ToUpper(value)
+	case "DTSTART":
+		event.Start, err = parseDate(params, value)
+	case "DTEND":
+		event.End, err = parseDate(params, value)
+	}
+
+	return err
+}
+
+func parseDate(params map[string]string, value string) (time.Time, error) {
+	if len(value) < len(dateLayout) {
+		return time.Time{}, fmt.Errorf("ical: invalid date %q", value)
+	}
+
+	if params["VALUE"] != "DATE" && len(value) > len(dateLayout) {
+		layout := dateTimeLayout
+		if strings.HasSuffix(value, "Z") {
+			layout += "Z"
+		}
+
+		_, err := time.Parse(layout, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("ical: invalid date-time %q", value)
+		}
+	}
+
+	// Only the date of a date-time matters for nightly occupancy
+	return time.Parse(dateLayout, value[:len(dateLayout)])
+}
+
+// splitLine splits a content line into its upper-cased name, parameters and value
+func splitLine(line string) (string, map[string]string, string) {
+	params := make(map[string]string)
+
+	colon := valueSeparator(line)
+	if colon < 0 {
+		return strings.ToUpper(line), params, ""
+	}
+
+	head, value := line[:colon], line[colon+1:]
+
+	parts := strings.Split(head, ";")
+	for _, part := range parts[1:] {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// valueSeparator returns the index of the colon which starts the value,
+// skipping colons inside quoted parameter values
+func valueSeparator(line string) int {
+	quoted := false
+
+	for i, c := range line {
+		switch c {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	lines := make([]string, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// fold splits line into CRLF terminated content lines of at most maxLineLength
+// octets without breaking UTF-8 sequences
+func fold(line string) string {
+	var sb strings.Builder
+
+	length := 0
+
+	for _, c := range line {
+		size := len(string(c))
+
+		if length+size > maxLineLength {
+			sb.WriteString("\r\n ")
+			// The leading space counts towards the length of continuation lines
+			length = 1
+		}
+
+		sb.WriteRune(c)
+		length += size
+	}
+
+	sb.WriteString("\r\n")
+
+	return sb.String()
+}
+
+var (
+	textEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	textUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
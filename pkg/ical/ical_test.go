@@ -0,0 +1,139 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestEncodeParse(t *testing.T) {
+	calendar := Calendar{
+		ProdID: "-//booking-service//rooms//EN",
+		Name:   "Room 1",
+		Events: []*Event{
+			{
+				UID:     "booking-1@booking-service",
+				Start:   date("2024-07-01"),
+				End:     date("2024-07-04"),
+				Summary: "Reserved; guest, family\nof three",
+			},
+			{
+				UID:     "block-2@booking-service",
+				Start:   date("2024-07-10"),
+				End:     date("2024-07-11"),
+				Summary: strings.Repeat("Maintenance ", 20),
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	err := Encode(&buf, &calendar, time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+	}
+
+	require.Contains(t, buf.String(), "DTSTART;VALUE=DATE:20240701\r\n")
+	require.Contains(t, buf.String(), "DTSTAMP:20240601T120000Z\r\n")
+	require.Contains(t, buf.String(), `SUMMARY:Reserved\; guest\, family\nof three`)
+
+	parsed, err := Parse(&buf)
+	require.NoError(t, err)
+	require.Equal(t, calendar.Name, parsed.Name)
+	require.Equal(t, calendar.ProdID, parsed.ProdID)
+	require.Equal(t, calendar.Events, parsed.Events)
+}
+
+func TestParse(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTIMEZONE",
+		"TZID:Asia/Tashkent",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:abc@other-channel",
+		"DTSTART;TZID=Asia/Tashkent:20240805T140000",
+		"DTEND;TZID=Asia/Tashkent:20240808T110000",
+		"SUMMARY:Not avail",
+		" able",
+		"BEGIN:VALARM",
+		"UID:alarm",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:def@other-channel",
+		"DTSTART;VALUE=DATE:20240901",
+		"STATUS:cancelled",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+
+	calendar, err := Parse(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, calendar.Events, 2)
+
+	require.Equal(t, &Event{
+		UID:     "abc@other-channel",
+		Start:   date("2024-08-05"),
+		End:     date("2024-08-08"),
+		Summary: "Not available",
+	}, calendar.Events[0])
+
+	require.Equal(t, &Event{
+		UID:    "def@other-channel",
+		Start:  date("2024-09-01"),
+		End:    date("2024-09-02"),
+		Status: StatusCancelled,
+	}, calendar.Events[1])
+}
+
+func TestParseInvalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{
+			name: "no calendar",
+			data: "hello",
+			err:  ErrNoCalendar,
+		},
+		{
+			name: "missing uid",
+			data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20240901\nEND:VEVENT\nEND:VCALENDAR",
+			err:  ErrMissingUID,
+		},
+		{
+			name: "missing start",
+			data: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:1\nEND:VEVENT\nEND:VCALENDAR",
+			err:  ErrNoStart,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tc.data))
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+
+	_, err := Parse(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:1\nDTSTART:2024\nEND:VEVENT\nEND:VCALENDAR"))
+	require.Error(t, err)
+}
@@ -0,0 +1,138 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// JWK is a public key in the JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// Ed25519
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the key ring. HMAC keys are secret and
+// are left out.
+func (kr *KeyRing) JWKS() JWKSet {
+	result := JWKSet{
+		Keys: make([]JWK, 0),
+	}
+
+	for _, id := range kr.order {
+		key := kr.keys[id]
+
+		jwk := JWK{
+			Kid: key.ID,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+
+		switch k := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(k.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(k)
+		default:
+			continue
+		}
+
+		result.Keys = append(result.Keys, jwk)
+	}
+
+	return result
+}
+
+// Key converts the JWK to a key which only verifies tokens
+func (jwk JWK) Key() (*Key, error) {
+	key := Key{
+		ID: jwk.Kid,
+	}
+
+	switch {
+	case jwk.Kty == "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+
+		key.Method = jwt.SigningMethodRS256
+		key.verifyKey = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size %d", len(x))
+		}
+
+		key.Method = jwt.SigningMethodEdDSA
+		key.verifyKey = ed25519.PublicKey(x)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if jwk.Alg != "" && jwk.Alg != key.Method.Alg() {
+		return nil, ErrUnsupportedKey
+	}
+
+	return &key, nil
+}
+
+// FromJWKS creates a key ring which verifies tokens signed by the keys of
+// the set, e.g. those of an external identity provider. Keys of unsupported
+// types are skipped. The key ring cannot sign.
+func FromJWKS(set JWKSet) (*KeyRing, error) {
+	kr := KeyRing{
+		keys: make(map[string]*Key),
+	}
+
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.Key()
+		if err != nil {
+			continue
+		}
+
+		if _, ok := kr.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		kr.keys[key.ID] = key
+		kr.order = append(kr.order, key.ID)
+	}
+
+	return &kr, nil
+}
@@ -0,0 +1,210 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrNoSigningKey   = errors.New("signing key is not configured")
+	ErrUnknownKey     = errors.New("unknown key id")
+	ErrNotPrivateKey  = errors.New("signing key must be a private key")
+	ErrUnsupportedKey = errors.New("unsupported key type, expected RSA or Ed25519")
+	ErrInvalidPEM     = errors.New("no PEM block found")
+)
+
+// Key is a key used to sign or verify tokens
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	// signKey is nil for keys which only verify tokens
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeyRing holds the key which signs new tokens and every key which may have
+// signed a token still in use. Rotating keys is done by adding the new key,
+// making it the signing key and removing the old one once its tokens expire.
+type KeyRing struct {
+	signing *Key
+	keys    map[string]*Key
+	// order keeps keys in the order they were added for the JWKS
+	order []string
+}
+
+// New creates a key ring signing with the key of signingKeyID
+func New(signingKeyID string, keys ...*Key) (*KeyRing, error) {
+	kr := KeyRing{
+		keys: make(map[string]*Key),
+	}
+
+	for _, key := range keys {
+		if _, ok := kr.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		kr.keys[key.ID] = key
+		kr.order = append(kr.order, key.ID)
+	}
+
+	signing, ok := kr.keys[signingKeyID]
+	if !ok {
+		return nil, ErrNoSigningKey
+	}
+
+	if signing.signKey == nil {
+		return nil, ErrNotPrivateKey
+	}
+
+	kr.signing = signing
+
+	return &kr, nil
+}
+
+// NewHMACKey creates an HS256 key. HMAC keys are never published in the JWKS.
+func NewHMACKey(id string, secret []byte) *Key {
+	return &Key{
+		ID:        id,
+		Method:    jwt.SigningMethodHS256,
+		signKey:   secret,
+		verifyKey: secret,
+	}
+}
+
+// ParseKey parses a PEM encoded RSA or Ed25519 key. A private key both signs
+// and verifies, a public key only verifies tokens of a retired key.
+func ParseKey(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+
+	var (
+		parsed interface{}
+		err    error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	key := Key{
+		ID: id,
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method = jwt.SigningMethodRS256
+		key.signKey = k
+		key.verifyKey = &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+		key.verifyKey = k
+	case ed25519.PrivateKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.signKey = k
+		key.verifyKey = k.Public()
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+		key.verifyKey = k
+	default:
+		return nil, ErrUnsupportedKey
+	}
+
+	return &key, nil
+}
+
+// Load creates a key ring from the configuration. keys is a comma separated
+// list of id=path pairs of PEM files. secret, if not empty, is an HS256 key
+// with an empty id, which verifies tokens issued without a key id and signs
+// when signingKeyID is empty.
+func Load(secret, keys, signingKeyID string) (*KeyRing, error) {
+	result := make([]*Key, 0)
+
+	if secret != "" {
+		result = append(result, NewHMACKey("", []byte(secret)))
+	}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, path, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("invalid key entry %q, expected id=path", entry)
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParseKey(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", id, err)
+		}
+
+		result = append(result, key)
+	}
+
+	return New(signingKeyID, result...)
+}
+
+// SigningKey returns the key which signs new tokens
+func (kr *KeyRing) SigningKey() *Key {
+	return kr.signing
+}
+
+// Sign signs the claims with the signing key, setting its id as kid
+func (kr *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	if kr.signing == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(kr.signing.Method, claims)
+
+	if kr.signing.ID != "" {
+		token.Header["kid"] = kr.signing.ID
+	}
+
+	return token.SignedString(kr.signing.signKey)
+}
+
+// Keyfunc finds the key of a token by its kid. Tokens must use the algorithm
+// of their key, so that e.g. a public key cannot be used as an HMAC secret.
+func (kr *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := kr.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.verifyKey, nil
+}
@@ -0,0 +1,164 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, typ string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func parse(kr *KeyRing, token string) (*jwt.StandardClaims, error) {
+	var claims jwt.StandardClaims
+	_, err := jwt.ParseWithClaims(token, &claims, kr.Keyfunc)
+	return &claims, err
+}
+
+func TestRotation(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	rsaPublic, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	edPrivate, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	oldPath := writePEM(t, "old.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	oldPublicPath := writePEM(t, "old.pub.pem", "PUBLIC KEY", rsaPublic)
+	newPath := writePEM(t, "new.pem", "PRIVATE KEY", edPrivate)
+
+	// Before the rotation the old key signs
+	before, err := Load("secret", "old="+oldPath, "old")
+	require.NoError(t, err)
+	require.Equal(t, jwt.SigningMethodRS256, before.SigningKey().Method)
+
+	oldToken, err := before.Sign(&jwt.StandardClaims{Subject: "1"})
+	require.NoError(t, err)
+
+	// After it the new key signs and the old one only verifies
+	after, err := Load("", "new="+newPath+", old="+oldPublicPath, "new")
+	require.NoError(t, err)
+	require.Equal(t, jwt.SigningMethodEdDSA, after.SigningKey().Method)
+
+	newToken, err := after.Sign(&jwt.StandardClaims{Subject: "2"})
+	require.NoError(t, err)
+
+	claims, err := parse(after, oldToken)
+	require.NoError(t, err)
+	require.Equal(t, "1", claims.Subject)
+
+	claims, err = parse(after, newToken)
+	require.NoError(t, err)
+	require.Equal(t, "2", claims.Subject)
+
+	_, err = parse(before, newToken)
+	require.Error(t, err)
+
+	jwks := after.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "new", jwks.Keys[0].Kid)
+	require.Equal(t, "OKP", jwks.Keys[0].Kty)
+	require.Equal(t, "old", jwks.Keys[1].Kid)
+	require.Equal(t, "RSA", jwks.Keys[1].Kty)
+	require.Equal(t, "AQAB", jwks.Keys[1].E)
+
+	// A retired key cannot sign
+	_, err = Load("", "old="+oldPublicPath, "old")
+	require.ErrorIs(t, err, ErrNotPrivateKey)
+}
+
+func TestHMAC(t *testing.T) {
+	kr, err := Load("secret", "", "")
+	require.NoError(t, err)
+	require.Len(t, kr.JWKS().Keys, 0)
+
+	token, err := kr.Sign(&jwt.StandardClaims{Subject: "1"})
+	require.NoError(t, err)
+
+	_, err = parse(kr, token)
+	require.NoError(t, err)
+
+	other, err := Load("other", "", "")
+	require.NoError(t, err)
+
+	_, err = parse(other, token)
+	require.Error(t, err)
+}
+
+func TestAlgorithmConfusion(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := writePEM(t, "key.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	kr, err := Load("", "key="+path, "key")
+	require.NoError(t, err)
+
+	// An HS256 token with the kid of an RSA key must not verify
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.StandardClaims{Subject: "1"})
+	token.Header["kid"] = "key"
+
+	signed, err := token.SignedString([]byte("guess"))
+	require.NoError(t, err)
+
+	_, err = parse(kr, signed)
+	require.Error(t, err)
+
+	_, err = Load("", "key", "key")
+	require.Error(t, err)
+
+	_, err = Load("", "", "missing")
+	require.ErrorIs(t, err, ErrNoSigningKey)
+}
+
+func TestFromJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	edPrivate, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	rsaPath := writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+	edPath := writePEM(t, "ed.pem", "PRIVATE KEY", edPrivate)
+
+	signer, err := Load("", "rsa="+rsaPath+",ed="+edPath, "rsa")
+	require.NoError(t, err)
+
+	token, err := signer.Sign(&jwt.StandardClaims{Subject: "1"})
+	require.NoError(t, err)
+
+	set := signer.JWKS()
+	set.Keys = append(set.Keys, JWK{Kty: "EC", Kid: "ec", Crv: "P-256"})
+
+	verifier, err := FromJWKS(set)
+	require.NoError(t, err)
+
+	claims, err := parse(verifier, token)
+	require.NoError(t, err)
+	require.Equal(t, "1", claims.Subject)
+
+	_, err = verifier.Sign(&jwt.StandardClaims{Subject: "2"})
+	require.ErrorIs(t, err, ErrNoSigningKey)
+}
@@ -0,0 +1,145 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage"
+)
+
+type Config struct {
+	// MaxAttempts is the number of failures within Window which locks a key
+	MaxAttempts int64
+	Window      time.Duration
+	// BaseLockout is the duration of the first lockout. Each following
+	// lockout within MemoryWindow lasts twice as long, up to MaxLockout.
+	BaseLockout  time.Duration
+	MaxLockout   time.Duration
+	MemoryWindow time.Duration
+}
+
+// Limiter locks keys, such as an email or an IP address, out after too many
+// failed attempts
+type Limiter struct {
+	store  storage.InMemoryStorageI
+	prefix string
+	cfg    Config
+}
+
+// New creates a limiter whose keys in the store start with prefix
+func New(store storage.InMemoryStorageI, prefix string, cfg Config) *Limiter {
+	return &Limiter{
+		store:  store,
+		prefix: prefix,
+		cfg:    cfg,
+	}
+}
+
+func (l *Limiter) failuresKey(key string) string {
+	return l.prefix + "failures_" + key
+}
+
+func (l *Limiter) lockKey(key string) string {
+	return l.prefix + "lock_" + key
+}
+
+func (l *Limiter) levelKey(key string) string {
+	return l.prefix + "level_" + key
+}
+
+// Check returns how long the keys stay locked, or 0 if none is
+func (l *Limiter) Check(ctx context.Context, keys ...string) (time.Duration, error) {
+	var result time.Duration
+
+	for _, key := range keys {
+		ttl, err := l.store.TTL(ctx, l.lockKey(key))
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		if ttl > result {
+			result = ttl
+		}
+	}
+
+	return result, nil
+}
+
+// Fail records a failed attempt for each key. It returns the longest lockout
+// started by this failure, or 0 if none was.
+func (l *Limiter) Fail(ctx context.Context, keys ...string) (time.Duration, error) {
+	var result time.Duration
+
+	for _, key := range keys {
+		failures, err := l.store.Incr(ctx, l.failuresKey(key), l.cfg.Window)
+		if err != nil {
+			return 0, err
+		}
+
+		if failures < l.cfg.MaxAttempts {
+			continue
+		}
+
+		lockout, err := l.lock(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+
+		if lockout > result {
+			result = lockout
+		}
+	}
+
+	return result, nil
+}
+
+func (l *Limiter) lock(ctx context.Context, key string) (time.Duration, error) {
+	level, err := l.store.Incr(ctx, l.levelKey(key), l.cfg.MemoryWindow)
+	if err != nil {
+		return 0, err
+	}
+
+	lockout := l.cfg.BaseLockout
+	for i := int64(1); i < level && lockout < l.cfg.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	if lockout > l.cfg.MaxLockout {
+		lockout = l.cfg.MaxLockout
+	}
+
+	err = l.store.Set(ctx, l.lockKey(key), strconv.FormatInt(level, 10), lockout)
+	if err != nil {
+		return 0, err
+	}
+
+	// The attempts after the lockout are counted afresh
+	err = l.store.Delete(ctx, l.failuresKey(key))
+	if err != nil {
+		return 0, err
+	}
+
+	return lockout, nil
+}
+
+// Reset forgets the failures of the keys, e.g. after a successful attempt
+func (l *Limiter) Reset(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		err := l.store.Delete(ctx, l.failuresKey(key))
+		if err != nil {
+			return err
+		}
+
+		err = l.store.Delete(ctx, l.levelKey(key))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package lockout
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage"
+	"github.com/stretchr/testify/require"
+)
+
+type entry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// fakeStore is an in-memory store with a manual clock
+type fakeStore struct {
+	now     time.Time
+	entries map[string]*entry
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		now:     time.Now(),
+		entries: make(map[string]*entry),
+	}
+}
+
+func (s *fakeStore) get(key string) *entry {
+	e, ok := s.entries[key]
+	if !ok || !s.now.Before(e.expiresAt) {
+		delete(s.entries, key)
+		return nil
+	}
+	return e
+}
+
+func (s *fakeStore) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	s.entries[key] = &entry{value: value, expiresAt: s.now.Add(exp)}
+	return nil
+}
+
+func (s *fakeStore) SetNX(ctx context.Context, key, value string, exp time.Duration) (bool, error) {
+	if s.get(key) != nil {
+		return false, nil
+	}
+	return true, s.Set(ctx, key, value, exp)
+}
+
+func (s *fakeStore) Get(ctx context.Context, key string) (string, error) {
+	e := s.get(key)
+	if e == nil {
+		return "", storage.ErrKeyNotFound
+	}
+	return e.value, nil
+}
+
+func (s *fakeStore) GetDel(ctx context.Context, key string) (string, error) {
+	value, err := s.Get(ctx, key)
+	delete(s.entries, key)
+	return value, err
+}
+
+func (s *fakeStore) Delete(ctx context.Context, key string) error {
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *fakeStore) Incr(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	e := s.get(key)
+	if e == nil {
+		e = &entry{value: "0", expiresAt: s.now.Add(exp)}
+		s.entries[key] = e
+	}
+
+	n, err := strconv.ParseInt(e.value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	n++
+	e.value = strconv.FormatInt(n, 10)
+
+	return n, nil
+}
+
+func (s *fakeStore) TTL(ctx context.Context, key string) (time.Duration, error) {
+	e := s.get(key)
+	if e == nil {
+		return 0, storage.ErrKeyNotFound
+	}
+	return e.expiresAt.Sub(s.now), nil
+}
+
+var testConfig = Config{
+	MaxAttempts:  3,
+	Window:       10 * time.Minute,
+	BaseLockout:  time.Minute,
+	MaxLockout:   3 * time.Minute,
+	MemoryWindow: time.Hour,
+}
+
+func TestExponentialLockout(t *testing.T) {
+	store := newFakeStore()
+	limiter := New(store, "login_", testConfig)
+
+	expected := []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+
+	for _, lockout := range expected {
+		for i := int64(1); i < testConfig.MaxAttempts; i++ {
+			locked, err := limiter.Fail(context.Background(), "email")
+			require.NoError(t, err)
+			require.Zero(t, locked)
+		}
+
+		locked, err := limiter.Fail(context.Background(), "email")
+		require.NoError(t, err)
+		require.Equal(t, lockout, locked)
+
+		retryAfter, err := limiter.Check(context.Background(), "ip", "email")
+		require.NoError(t, err)
+		require.Equal(t, lockout, retryAfter)
+
+		store.now = store.now.Add(lockout)
+
+		retryAfter, err = limiter.Check(context.Background(), "ip", "email")
+		require.NoError(t, err)
+		require.Zero(t, retryAfter)
+	}
+}
+
+func TestReset(t *testing.T) {
+	store := newFakeStore()
+	limiter := New(store, "login_", testConfig)
+
+	for i := int64(1); i < testConfig.MaxAttempts; i++ {
+		_, err := limiter.Fail(context.Background(), "email")
+		require.NoError(t, err)
+	}
+
+	err := limiter.Reset(context.Background(), "email")
+	require.NoError(t, err)
+
+	locked, err := limiter.Fail(context.Background(), "email")
+	require.NoError(t, err)
+	require.Zero(t, locked)
+}
+
+func TestWindow(t *testing.T) {
+	store := newFakeStore()
+	limiter := New(store, "login_", testConfig)
+
+	for i := int64(1); i < testConfig.MaxAttempts; i++ {
+		_, err := limiter.Fail(context.Background(), "email")
+		require.NoError(t, err)
+	}
+
+	// Failures older than the window are forgotten
+	store.now = store.now.Add(testConfig.Window)
+
+	locked, err := limiter.Fail(context.Background(), "email")
+	require.NoError(t, err)
+	require.Zero(t, locked)
+}
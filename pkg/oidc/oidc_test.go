@@ -0,0 +1,235 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/ibrat-muslim/booking-service/pkg/keyring"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "client"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost/callback"
+	testCode         = "code"
+)
+
+// stubProvider is a minimal OpenID Connect provider which issues a token
+// for testCode
+type stubProvider struct {
+	*httptest.Server
+	t    *testing.T
+	keys *keyring.KeyRing
+	// challenge is the PKCE challenge the code was issued with
+	challenge string
+	// claims are signed into the ID token
+	claims jwt.MapClaims
+}
+
+func newKeyRing(t *testing.T, id string) *keyring.KeyRing {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	key, err := keyring.ParseKey(id, data)
+	require.NoError(t, err)
+
+	kr, err := keyring.New(id, key)
+	require.NoError(t, err)
+
+	return kr
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	s := &stubProvider{
+		t:    t,
+		keys: newKeyRing(t, "key-1"),
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.keys.JWKS())
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		if r.PostForm.Get("grant_type") != "authorization_code" ||
+			r.PostForm.Get("code") != testCode ||
+			r.PostForm.Get("client_id") != testClientID ||
+			r.PostForm.Get("client_secret") != testClientSecret ||
+			r.PostForm.Get("redirect_uri") != testRedirectURL ||
+			CodeChallenge(r.PostForm.Get("code_verifier")) != s.challenge {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		idToken, err := s.keys.Sign(s.claims)
+		require.NoError(t, err)
+
+		writeJSON(w, http.StatusOK, map[string]string{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// authorize plays the user signing in, returning the PKCE verifier
+func (s *stubProvider) authorize(p *Provider, nonce string) string {
+	verifier, err := RandomToken()
+	require.NoError(s.t, err)
+
+	authURL, err := p.AuthCodeURL("state", nonce, verifier)
+	require.NoError(s.t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(s.t, err)
+	require.Equal(s.t, s.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+	query := u.Query()
+	require.Equal(s.t, "code", query.Get("response_type"))
+	require.Equal(s.t, testClientID, query.Get("client_id"))
+	require.Equal(s.t, testRedirectURL, query.Get("redirect_uri"))
+	require.Equal(s.t, "openid email profile", query.Get("scope"))
+	require.Equal(s.t, "state", query.Get("state"))
+	require.Equal(s.t, nonce, query.Get("nonce"))
+	require.Equal(s.t, "S256", query.Get("code_challenge_method"))
+
+	s.challenge = query.Get("code_challenge")
+
+	return verifier
+}
+
+func (s *stubProvider) provider() *Provider {
+	return New(Config{
+		Issuer:       s.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"email", "profile"},
+	}, s.Client())
+}
+
+func (s *stubProvider) setClaims(nonce string) {
+	s.claims = jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            "subject-1",
+		"aud":            []string{testClientID},
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "john@example.com",
+		"email_verified": true,
+		"given_name":     "John",
+		"family_name":    "Doe",
+	}
+}
+
+func TestExchange(t *testing.T) {
+	s := newStubProvider(t)
+	p := s.provider()
+
+	verifier := s.authorize(p, "nonce")
+	s.setClaims("nonce")
+
+	claims, err := p.Exchange(testCode, verifier, "nonce")
+	require.NoError(t, err)
+	require.Equal(t, "subject-1", claims.Subject)
+	require.Equal(t, "john@example.com", claims.Email)
+	require.True(t, claims.EmailVerified)
+	require.Equal(t, "John", claims.GivenName)
+	require.Equal(t, "Doe", claims.FamilyName)
+
+	// The provider rotates its key, which is fetched again
+	s.keys = newKeyRing(t, "key-2")
+
+	claims, err = p.Exchange(testCode, verifier, "nonce")
+	require.NoError(t, err)
+	require.Equal(t, "subject-1", claims.Subject)
+}
+
+func TestExchangeRejects(t *testing.T) {
+	s := newStubProvider(t)
+	p := s.provider()
+
+	verifier := s.authorize(p, "nonce")
+
+	testCases := []struct {
+		name     string
+		verifier string
+		nonce    string
+		claims   func(jwt.MapClaims)
+	}{
+		{name: "wrong verifier", verifier: "other"},
+		{name: "wrong nonce", nonce: "other"},
+		{name: "other audience", claims: func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{name: "other issuer", claims: func(c jwt.MapClaims) { c["iss"] = "https://example.com" }},
+		{name: "expired", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s.setClaims("nonce")
+			if tc.claims != nil {
+				tc.claims(s.claims)
+			}
+
+			v := verifier
+			if tc.verifier != "" {
+				v = tc.verifier
+			}
+
+			nonce := "nonce"
+			if tc.nonce != "" {
+				nonce = tc.nonce
+			}
+
+			_, err := p.Exchange(testCode, v, nonce)
+			require.Error(t, err)
+		})
+	}
+
+	// A token signed by a key the provider does not publish
+	s.setClaims("nonce")
+	forged, err := newKeyRing(t, "key-1").Sign(s.claims)
+	require.NoError(t, err)
+
+	d, err := p.getDiscovery()
+	require.NoError(t, err)
+
+	_, err = p.verify(d, forged)
+	require.ErrorIs(t, err, ErrInvalidIDToken)
+}
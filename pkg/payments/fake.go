@@ -0,0 +1,155 @@
+package payments
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"sync"
+)
+
+// DeclinedSource is a payment source which the fake provider always declines
+const DeclinedSource = "tok_declined"
+
+// FakeProvider is an in-process payment provider for development and tests.
+// It accepts every source except DeclinedSource and signs its webhook
+// callbacks with the configured secret.
+type FakeProvider struct {
+	mu       sync.Mutex
+	secret   []byte
+	payments map[string]*Payment
+	// refunds holds the idempotency keys of the refunds issued
+	refunds map[string]bool
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:   []byte(secret),
+		payments: make(map[string]*Payment),
+		refunds:  make(map[string]bool),
+	}
+}
+
+func (f *FakeProvider) Authorize(req *AuthorizeRequest) (*Payment, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	if req.Source == DeclinedSource {
+		return nil, ErrDeclined
+	}
+
+	id, err := randomID("fake_pay_")
+	if err != nil {
+		return nil, err
+	}
+
+	payment := Payment{
+		ID:     id,
+		Status: StatusAuthorized,
+		Amount: req.Amount,
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.payments[id] = &payment
+
+	result := payment
+	return &result, nil
+}
+
+func (f *FakeProvider) Capture(paymentID string, amount float64) (*Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[paymentID]
+	if !ok {
+		return nil, ErrPaymentNotFound
+	}
+
+	if payment.Status != StatusAuthorized {
+		return nil, ErrInvalidState
+	}
+
+	if amount <= 0 || amount > payment.Amount {
+		return nil, ErrInvalidAmount
+	}
+
+	payment.Status = StatusCaptured
+	payment.CapturedAmount = amount
+
+	result := *payment
+	return &result, nil
+}
+
+func (f *FakeProvider) Refund(paymentID string, amount float64, idempotencyKey string) (*Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[paymentID]
+	if !ok {
+		return nil, ErrPaymentNotFound
+	}
+
+	if idempotencyKey != "" && f.refunds[idempotencyKey] {
+		result := *payment
+		return &result, nil
+	}
+
+	if payment.Status != StatusCaptured && payment.Status != StatusRefunded {
+		return nil, ErrInvalidState
+	}
+
+	refunded := math.Round((payment.RefundedAmount+amount)*100) / 100
+	if amount <= 0 || refunded > payment.CapturedAmount {
+		return nil, ErrInvalidAmount
+	}
+
+	payment.Status = StatusRefunded
+	payment.RefundedAmount = refunded
+
+	if idempotencyKey != "" {
+		f.refunds[idempotencyKey] = true
+	}
+
+	result := *payment
+	return &result, nil
+}
+
+func (f *FakeProvider) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	if !VerifySignature(f.secret, payload, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+
+	err := json.Unmarshal(payload, &event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+// Webhook returns the signed body of a webhook callback for event, as the
+// fake provider would send it
+func (f *FakeProvider) Webhook(event *Event) ([]byte, string, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return payload, Sign(f.secret, payload), nil
+}
+
+func randomID(prefix string) (string, error) {
+	b := make([]byte, 12)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + hex.EncodeToString(b), nil
+}
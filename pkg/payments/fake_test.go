@@ -0,0 +1,90 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider(t *testing.T) {
+	provider := NewFakeProvider("secret")
+
+	payment, err := provider.Authorize(&AuthorizeRequest{
+		Source:    "tok_visa",
+		Amount:    200,
+		Reference: "1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, StatusAuthorized, payment.Status)
+
+	_, err = provider.Refund(payment.ID, 50, "")
+	require.ErrorIs(t, err, ErrInvalidState)
+
+	_, err = provider.Capture(payment.ID, 250)
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	payment, err = provider.Capture(payment.ID, 200)
+	require.NoError(t, err)
+	require.Equal(t, StatusCaptured, payment.Status)
+	require.Equal(t, 200.0, payment.CapturedAmount)
+
+	_, err = provider.Capture(payment.ID, 200)
+	require.ErrorIs(t, err, ErrInvalidState)
+
+	payment, err = provider.Refund(payment.ID, 120, "refund_1")
+	require.NoError(t, err)
+	require.Equal(t, StatusRefunded, payment.Status)
+	require.Equal(t, 120.0, payment.RefundedAmount)
+
+	// A repeated refund is not issued again
+	payment, err = provider.Refund(payment.ID, 120, "refund_1")
+	require.NoError(t, err)
+	require.Equal(t, 120.0, payment.RefundedAmount)
+
+	_, err = provider.Refund(payment.ID, 100, "")
+	require.ErrorIs(t, err, ErrInvalidAmount)
+
+	payment, err = provider.Refund(payment.ID, 80, "refund_2")
+	require.NoError(t, err)
+	require.Equal(t, 200.0, payment.RefundedAmount)
+
+	_, err = provider.Capture("unknown", 10)
+	require.ErrorIs(t, err, ErrPaymentNotFound)
+}
+
+func TestFakeProviderDeclined(t *testing.T) {
+	provider := NewFakeProvider("secret")
+
+	_, err := provider.Authorize(&AuthorizeRequest{
+		Source: DeclinedSource,
+		Amount: 200,
+	})
+	require.ErrorIs(t, err, ErrDeclined)
+}
+
+func TestFakeProviderWebhook(t *testing.T) {
+	provider := NewFakeProvider("secret")
+
+	event := Event{
+		ID:        "evt_1",
+		Type:      EventCaptured,
+		PaymentID: "fake_pay_1",
+		Amount:    99.5,
+	}
+
+	payload, signature, err := provider.Webhook(&event)
+	require.NoError(t, err)
+
+	verified, err := provider.VerifyWebhook(payload, signature)
+	require.NoError(t, err)
+	require.Equal(t, &event, verified)
+
+	_, err = provider.VerifyWebhook(payload, Sign([]byte("other"), payload))
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = provider.VerifyWebhook(append(payload, ' '), signature)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = provider.VerifyWebhook(payload, "not hex")
+	require.ErrorIs(t, err, ErrInvalidSignature)
+}
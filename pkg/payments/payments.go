@@ -0,0 +1,88 @@
+package payments
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// Types of events sent to the webhook
+const (
+	EventCaptured = "payment.captured"
+	EventRefunded = "payment.refunded"
+	EventFailed   = "payment.failed"
+)
+
+// Statuses of a payment at the provider
+const (
+	StatusAuthorized = "authorized"
+	StatusCaptured   = "captured"
+	StatusRefunded   = "refunded"
+)
+
+var (
+	ErrDeclined         = errors.New("payment declined")
+	ErrPaymentNotFound  = errors.New("payment not found")
+	ErrInvalidAmount    = errors.New("invalid payment amount")
+	ErrInvalidState     = errors.New("payment is not in a state allowing this operation")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+type AuthorizeRequest struct {
+	// Source is the tokenized payment method, e.g. a card token
+	Source    string
+	Amount    float64
+	Reference string
+}
+
+// Payment is the state of a payment at the provider
+type Payment struct {
+	ID             string
+	Status         string
+	Amount         float64
+	CapturedAmount float64
+	RefundedAmount float64
+}
+
+// Event is a verified webhook callback. Amount is the total captured
+// or refunded so far, depending on Type.
+type Event struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"`
+	PaymentID string  `json:"payment_id"`
+	Amount    float64 `json:"amount"`
+}
+
+// PaymentProvider is a payment gateway. Funds are first authorized, then
+// captured, and captured funds may be refunded in one or more parts.
+type PaymentProvider interface {
+	Authorize(req *AuthorizeRequest) (*Payment, error)
+	Capture(paymentID string, amount float64) (*Payment, error)
+	// Refund pays back amount of the captured funds. A refund repeated with
+	// the same idempotency key is not issued again, the current state of the
+	// payment is returned instead.
+	Refund(paymentID string, amount float64, idempotencyKey string) (*Payment, error)
+	// VerifyWebhook checks the signature of a webhook callback and parses it
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of payload
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is the HMAC-SHA256 of payload
+func VerifySignature(secret, payload []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	return hmac.Equal(mac.Sum(nil), expected)
+}
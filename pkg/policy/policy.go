@@ -0,0 +1,144 @@
+package policy
+
+import "github.com/ibrat-muslim/booking-service/storage/repo"
+
+// Permission is an action on a kind of resource
+type Permission string
+
+const (
+	UserCreate Permission = "user:create"
+	UserUpdate Permission = "user:update"
+	UserDelete Permission = "user:delete"
+	// UserChangeType allows changing the type, i.e. the role, of a user
+	UserChangeType Permission = "user:change_type"
+	UserBan        Permission = "user:ban"
+
+	CategoryWrite Permission = "category:write"
+
+	PostCreate Permission = "post:create"
+	PostUpdate Permission = "post:update"
+	PostDelete Permission = "post:delete"
+
+	CommentCreate Permission = "comment:create"
+	CommentUpdate Permission = "comment:update"
+	CommentDelete Permission = "comment:delete"
+
+	LikeWrite Permission = "like:write"
+
+	PropertyCreate Permission = "property:create"
+	// PropertyManage covers the property and everything belonging to it,
+	// such as rooms, pricing rules, blocks, calendars and review replies
+	PropertyManage Permission = "property:manage"
+
+	BookingRead   Permission = "booking:read"
+	BookingCreate Permission = "booking:create"
+	BookingManage Permission = "booking:manage"
+
+	ReportRead Permission = "report:read"
+
+	TwoFactorPolicyManage Permission = "two_factor_policy:manage"
+)
+
+// Grant is how far a permission extends
+type Grant int
+
+const (
+	None Grant = iota
+	// Own allows the action only on resources owned by the subject
+	Own
+	// Any allows the action on every resource
+	Any
+)
+
+var roles = map[string]map[Permission]Grant{
+	repo.UserTypeSuperAdmin: {
+		UserCreate:            Any,
+		UserUpdate:            Any,
+		UserDelete:            Any,
+		UserChangeType:        Any,
+		UserBan:               Any,
+		CategoryWrite:         Any,
+		PostCreate:            Any,
+		PostUpdate:            Any,
+		PostDelete:            Any,
+		CommentCreate:         Any,
+		CommentUpdate:         Any,
+		CommentDelete:         Any,
+		LikeWrite:             Any,
+		PropertyCreate:        Any,
+		PropertyManage:        Any,
+		BookingRead:           Any,
+		BookingManage:         Any,
+		ReportRead:            Any,
+		TwoFactorPolicyManage: Any,
+	},
+	repo.UserTypeOwner: {
+		UserUpdate:     Own,
+		UserDelete:     Own,
+		PostCreate:     Own,
+		PostUpdate:     Own,
+		PostDelete:     Own,
+		CommentCreate:  Own,
+		CommentUpdate:  Own,
+		CommentDelete:  Own,
+		LikeWrite:      Own,
+		PropertyCreate: Own,
+		PropertyManage: Own,
+		BookingRead:    Own,
+		BookingManage:  Own,
+		ReportRead:     Own,
+	},
+	repo.UserTypeGuest: {
+		UserUpdate:    Own,
+		UserDelete:    Own,
+		PostCreate:    Own,
+		PostUpdate:    Own,
+		PostDelete:    Own,
+		CommentCreate: Own,
+		CommentUpdate: Own,
+		CommentDelete: Own,
+		LikeWrite:     Own,
+		BookingRead:   Own,
+		BookingCreate: Own,
+	},
+}
+
+// Known reports whether p is a permission of any role
+func Known(p Permission) bool {
+	for _, permissions := range roles {
+		if _, ok := permissions[p]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Subject is the user acting
+type Subject struct {
+	ID   int64
+	Role string
+}
+
+// GrantOf returns how far the permission extends for the subject
+func GrantOf(s Subject, p Permission) Grant {
+	return roles[s.Role][p]
+}
+
+// Can reports whether the subject may perform the action at all, at least
+// on its own resources
+func Can(s Subject, p Permission) bool {
+	return GrantOf(s, p) != None
+}
+
+// CanOn reports whether the subject may perform the action on a resource
+// owned by ownerID
+func CanOn(s Subject, p Permission, ownerID int64) bool {
+	switch GrantOf(s, p) {
+	case Any:
+		return true
+	case Own:
+		return s.ID == ownerID
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanOn(t *testing.T) {
+	var (
+		admin  = Subject{ID: 1, Role: repo.UserTypeSuperAdmin}
+		owner  = Subject{ID: 2, Role: repo.UserTypeOwner}
+		guest  = Subject{ID: 3, Role: repo.UserTypeGuest}
+		other  = Subject{ID: 4, Role: repo.UserTypeGuest}
+		nobody = Subject{ID: 5, Role: "unknown"}
+	)
+
+	testCases := []struct {
+		name       string
+		subject    Subject
+		permission Permission
+		ownerID    int64
+		allowed    bool
+	}{
+		{"guest edits own post", guest, PostUpdate, guest.ID, true},
+		{"guest edits another user's post", guest, PostUpdate, other.ID, false},
+		{"guest deletes another user's post", guest, PostDelete, other.ID, false},
+		{"superadmin edits any post", admin, PostUpdate, guest.ID, true},
+		{"guest edits own comment", guest, CommentUpdate, guest.ID, true},
+		{"guest deletes another user's comment", guest, CommentDelete, other.ID, false},
+		{"guest updates own profile", guest, UserUpdate, guest.ID, true},
+		{"guest updates another user", guest, UserUpdate, other.ID, false},
+		{"guest deletes another user", guest, UserDelete, other.ID, false},
+		{"guest changes own type", guest, UserChangeType, guest.ID, false},
+		{"superadmin deletes any user", admin, UserDelete, guest.ID, true},
+		{"owner manages own property", owner, PropertyManage, owner.ID, true},
+		{"owner manages another property", owner, PropertyManage, other.ID, false},
+		{"guest manages own property", guest, PropertyManage, guest.ID, false},
+		{"superadmin manages any property", admin, PropertyManage, owner.ID, true},
+		{"unknown role edits own post", nobody, PostUpdate, nobody.ID, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.allowed, CanOn(tc.subject, tc.permission, tc.ownerID))
+		})
+	}
+}
+
+func TestKnown(t *testing.T) {
+	require.True(t, Known(BookingCreate))
+	require.True(t, Known(TwoFactorPolicyManage))
+	require.False(t, Known("booking:everything"))
+}
+
+func TestCan(t *testing.T) {
+	testCases := []struct {
+		role       string
+		permission Permission
+		allowed    bool
+	}{
+		{repo.UserTypeSuperAdmin, UserCreate, true},
+		{repo.UserTypeOwner, UserCreate, false},
+		{repo.UserTypeGuest, UserCreate, false},
+		{repo.UserTypeSuperAdmin, CategoryWrite, true},
+		{repo.UserTypeOwner, CategoryWrite, false},
+		{repo.UserTypeGuest, BookingCreate, true},
+		{repo.UserTypeOwner, BookingCreate, false},
+		{repo.UserTypeSuperAdmin, BookingCreate, false},
+		{repo.UserTypeOwner, PropertyCreate, true},
+		{repo.UserTypeGuest, PropertyCreate, false},
+		{repo.UserTypeOwner, ReportRead, true},
+		{repo.UserTypeGuest, ReportRead, false},
+		{repo.UserTypeGuest, UserBan, false},
+		{repo.UserTypeOwner, TwoFactorPolicyManage, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.role+" "+string(tc.permission), func(t *testing.T) {
+			require.Equal(t, tc.allowed, Can(Subject{ID: 1, Role: tc.role}, tc.permission))
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Types of pricing rules
+const (
+	RuleTypeSeason       = "season"
+	RuleTypeWeekday      = "weekday"
+	RuleTypeMinNights    = "min_nights"
+	RuleTypeLengthOfStay = "length_of_stay"
+)
+
+// MinNightsError is returned when a stay is shorter than a minimum-night rule requires
+type MinNightsError struct {
+	MinNights int
+}
+
+func (e *MinNightsError) Error() string {
+	return fmt.Sprintf("minimum stay is %d nights", e.MinNights)
+}
+
+// Rule is a pricing rule attached to a room. Which fields are used depends on Type:
+// season overrides the nightly price between StartDate and EndDate,
+// weekday multiplies the nightly price on DaysOfWeek by Multiplier,
+// min_nights requires at least MinNights for stays starting between StartDate and EndDate (or always if unset),
+// length_of_stay takes DiscountPercent off stays of at least MinNights.
+type Rule struct {
+	Type            string
+	StartDate       *time.Time
+	EndDate         *time.Time
+	DaysOfWeek      []time.Weekday
+	Price           float64
+	Multiplier      float64
+	MinNights       int
+	DiscountPercent float64
+}
+
+// Night is the price of a single night of a stay
+type Night struct {
+	Date  time.Time
+	Price float64
+}
+
+// Quote is the itemised price of a stay
+type Quote struct {
+	Nights          []*Night
+	Subtotal        float64
+	DiscountPercent float64
+	Discount        float64
+	Total           float64
+}
+
+// NightlyPrice returns the price of the night starting on date
+func NightlyPrice(basePrice float64, rules []*Rule, date time.Time) float64 {
+	price := basePrice
+
+	// Later season rules take precedence over earlier ones
+	for _, rule := range rules {
+		if rule.Type == RuleTypeSeason && rule.covers(date) {
+			price = rule.Price
+		}
+	}
+
+	for _, rule := range rules {
+		if rule.Type != RuleTypeWeekday {
+			continue
+		}
+
+		for _, day := range rule.DaysOfWeek {
+			if day == date.Weekday() {
+				price *= rule.Multiplier
+				break
+			}
+		}
+	}
+
+	return round(price)
+}
+
+// Calculate returns the quote of a stay from checkIn to checkOut
+func Calculate(basePrice float64, rules []*Rule, checkIn, checkOut time.Time) (*Quote, error) {
+	quote := Quote{
+		Nights: make([]*Night, 0),
+	}
+
+	for date := checkIn; date.Before(checkOut); date = date.AddDate(0, 0, 1) {
+		night := Night{
+			Date:  date,
+			Price: NightlyPrice(basePrice, rules, date),
+		}
+
+		quote.Nights = append(quote.Nights, &night)
+		quote.Subtotal += night.Price
+	}
+
+	nights := len(quote.Nights)
+
+	for _, rule := range rules {
+		switch rule.Type {
+		case RuleTypeMinNights:
+			if nights < rule.MinNights && (rule.StartDate == nil || rule.covers(checkIn)) {
+				return nil, &MinNightsError{MinNights: rule.MinNights}
+			}
+		case RuleTypeLengthOfStay:
+			// Only the best length-of-stay discount applies
+			if nights >= rule.MinNights && rule.DiscountPercent > quote.DiscountPercent {
+				quote.DiscountPercent = rule.DiscountPercent
+			}
+		}
+	}
+
+	quote.Subtotal = round(quote.Subtotal)
+	quote.Discount = round(quote.Subtotal * quote.DiscountPercent / 100)
+	quote.Total = round(quote.Subtotal - quote.Discount)
+
+	return &quote, nil
+}
+
+// covers reports whether date falls within the rule's date range, both ends inclusive
+func (r *Rule) covers(date time.Time) bool {
+	if r.StartDate == nil || r.EndDate == nil {
+		return false
+	}
+
+	return !date.Before(*r.StartDate) && !date.After(*r.EndDate)
+}
+
+func round(price float64) float64 {
+	return math.Round(price*100) / 100
+}
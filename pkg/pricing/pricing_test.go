@@ -0,0 +1,139 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func datePtr(s string) *time.Time {
+	d := date(s)
+	return &d
+}
+
+func TestCalculate(t *testing.T) {
+	rules := []*Rule{
+		{
+			Type:      RuleTypeSeason,
+			StartDate: datePtr("2024-07-01"),
+			EndDate:   datePtr("2024-08-31"),
+			Price:     150,
+		},
+		{
+			Type:       RuleTypeWeekday,
+			DaysOfWeek: []time.Weekday{time.Friday, time.Saturday},
+			Multiplier: 1.2,
+		},
+		{
+			Type:      RuleTypeMinNights,
+			StartDate: datePtr("2024-07-01"),
+			EndDate:   datePtr("2024-08-31"),
+			MinNights: 2,
+		},
+		{
+			Type:            RuleTypeLengthOfStay,
+			MinNights:       7,
+			DiscountPercent: 10,
+		},
+		{
+			Type:            RuleTypeLengthOfStay,
+			MinNights:       30,
+			DiscountPercent: 25,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		checkIn  string
+		checkOut string
+		nights   []float64
+		discount float64
+		total    float64
+		err      error
+	}{
+		{
+			name:     "base price on weekdays",
+			checkIn:  "2024-06-03", // Monday
+			checkOut: "2024-06-05",
+			nights:   []float64{100, 100},
+			total:    200,
+		},
+		{
+			name:     "weekend surcharge",
+			checkIn:  "2024-06-06", // Thursday
+			checkOut: "2024-06-09",
+			nights:   []float64{100, 120, 120},
+			total:    340,
+		},
+		{
+			name:     "season override across the boundary",
+			checkIn:  "2024-06-30", // Sunday
+			checkOut: "2024-07-02",
+			nights:   []float64{100, 150},
+			total:    250,
+		},
+		{
+			name:     "weekly discount",
+			checkIn:  "2024-06-03",
+			checkOut: "2024-06-10",
+			nights:   []float64{100, 100, 100, 100, 120, 120, 100},
+			discount: 74,
+			total:    666,
+		},
+		{
+			name:     "minimum nights in season",
+			checkIn:  "2024-07-10",
+			checkOut: "2024-07-11",
+			err:      &MinNightsError{MinNights: 2},
+		},
+		{
+			name:     "minimum nights outside season",
+			checkIn:  "2024-06-10",
+			checkOut: "2024-06-11",
+			nights:   []float64{100},
+			total:    100,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := Calculate(100, rules, date(tc.checkIn), date(tc.checkOut))
+			if tc.err != nil {
+				require.Equal(t, tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, quote.Nights, len(tc.nights))
+
+			for i, night := range quote.Nights {
+				require.Equal(t, tc.nights[i], night.Price)
+			}
+
+			require.Equal(t, tc.discount, quote.Discount)
+			require.Equal(t, tc.total, quote.Total)
+		})
+	}
+}
+
+func TestCalculateMonthlyDiscount(t *testing.T) {
+	rules := []*Rule{
+		{Type: RuleTypeLengthOfStay, MinNights: 7, DiscountPercent: 10},
+		{Type: RuleTypeLengthOfStay, MinNights: 30, DiscountPercent: 25},
+	}
+
+	quote, err := Calculate(100, rules, date("2024-03-01"), date("2024-03-31"))
+	require.NoError(t, err)
+	require.Equal(t, 3000.0, quote.Subtotal)
+	require.Equal(t, 25.0, quote.DiscountPercent)
+	require.Equal(t, 2250.0, quote.Total)
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage"
+)
+
+// sweepInterval is how often expired counters are removed
+const sweepInterval = time.Minute
+
+type memoryEntry struct {
+	value     int64
+	expiresAt time.Time
+}
+
+// memoryStore keeps the counters in process. Limits only hold within a
+// single instance, so it is meant for tests and local runs.
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() Store {
+	return newMemoryStore(time.Now)
+}
+
+func newMemoryStore(now func() time.Time) *memoryStore {
+	return &memoryStore{
+		entries:   make(map[string]*memoryEntry),
+		lastSweep: now(),
+		now:       now,
+	}
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || !s.now().Before(entry.expiresAt) {
+		return "", storage.ErrKeyNotFound
+	}
+
+	return strconv.FormatInt(entry.value, 10), nil
+}
+
+func (s *memoryStore) Incr(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, entry := range s.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	entry, ok := s.entries[key]
+	if !ok || !now.Before(entry.expiresAt) {
+		entry = &memoryEntry{expiresAt: now.Add(exp)}
+		s.entries[key] = entry
+	}
+
+	entry.value++
+
+	return entry.value, nil
+}
@@ -0,0 +1,217 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage"
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit, expected <requests>/<window> such as 100/1m")
+
+// Limit allows Requests requests per Window
+type Limit struct {
+	Requests int64
+	Window   time.Duration
+}
+
+// ParseLimit parses a limit such as "100/1m"
+func ParseLimit(s string) (Limit, error) {
+	parts := strings.Split(strings.TrimSpace(s), "/")
+	if len(parts) != 2 {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	requests, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || requests <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	window, err := time.ParseDuration(parts[1])
+	if err != nil || window <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+
+	return Limit{
+		Requests: requests,
+		Window:   window,
+	}, nil
+}
+
+// Rules holds the default limit and the limits of single routes
+type Rules struct {
+	// Default is shared by the routes without a limit of their own. A zero
+	// Default leaves them unlimited.
+	Default Limit
+	Routes  map[string]Limit
+}
+
+// LoadRules parses the default limit and a comma separated list of route
+// limits such as "POST /v1/auth/login=10/1m". Either may be empty.
+func LoadRules(defaultLimit, routes string) (*Rules, error) {
+	rules := Rules{
+		Routes: make(map[string]Limit),
+	}
+
+	if strings.TrimSpace(defaultLimit) != "" {
+		limit, err := ParseLimit(defaultLimit)
+		if err != nil {
+			return nil, err
+		}
+		rules.Default = limit
+	}
+
+	for _, entry := range strings.Split(routes, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, "=")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid route limit %q, expected <method> <path>=<limit>", entry)
+		}
+
+		route := strings.Fields(parts[0])
+		if len(route) != 2 {
+			return nil, fmt.Errorf("invalid route limit %q, expected <method> <path>=<limit>", entry)
+		}
+
+		limit, err := ParseLimit(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("route %q: %w", parts[0], err)
+		}
+
+		rules.Routes[routeKey(route[0], route[1])] = limit
+	}
+
+	return &rules, nil
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// Get returns the limit of the route and the name of the bucket counting
+// its requests, which is shared by all routes without a limit of their own
+func (r *Rules) Get(method, path string) (Limit, string) {
+	key := routeKey(method, path)
+
+	if limit, ok := r.Routes[key]; ok {
+		return limit, key
+	}
+
+	return r.Default, "default"
+}
+
+// Store keeps the counters. It is satisfied by storage.InMemoryStorageI.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Incr(ctx context.Context, key string, exp time.Duration) (int64, error)
+}
+
+// Result describes the state of a key after a request
+type Result struct {
+	Allowed   bool
+	Limit     int64
+	Remaining int64
+	// Reset is the time until the key can make a request again if it was
+	// not allowed, or else until the current window ends
+	Reset time.Duration
+}
+
+// Limiter counts requests with a sliding window, which estimates the
+// requests of the last window from the counters of the current and the
+// previous fixed windows
+type Limiter struct {
+	store  Store
+	prefix string
+	now    func() time.Time
+}
+
+// New creates a limiter whose counters in the store start with prefix
+func New(store Store, prefix string) *Limiter {
+	return &Limiter{
+		store:  store,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// Allow counts a request of key and reports whether it is within the limit.
+// The request is counted before it is decided on, so that concurrent
+// requests see each other. Requests which are not allowed are counted too,
+// and a client which keeps retrying stays limited.
+func (l *Limiter) Allow(ctx context.Context, key string, limit Limit) (*Result, error) {
+	now := l.now().UnixNano()
+	window := now / int64(limit.Window)
+	elapsed := time.Duration(now - window*int64(limit.Window))
+
+	currentKey := fmt.Sprintf("%s%s_%d", l.prefix, key, window)
+	previousKey := fmt.Sprintf("%s%s_%d", l.prefix, key, window-1)
+
+	previous, err := l.count(ctx, previousKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// The counter has to outlive the next window, in which it is the
+	// previous one
+	current, err := l.store.Incr(ctx, currentKey, 2*limit.Window)
+	if err != nil {
+		return nil, err
+	}
+
+	weight := 1 - float64(elapsed)/float64(limit.Window)
+	estimate := float64(previous)*weight + float64(current)
+
+	if estimate > float64(limit.Requests) {
+		return &Result{
+			Limit: limit.Requests,
+			Reset: retryAfter(previous, current, limit, elapsed),
+		}, nil
+	}
+
+	return &Result{
+		Allowed:   true,
+		Limit:     limit.Requests,
+		Remaining: limit.Requests - int64(math.Ceil(estimate)),
+		Reset:     limit.Window - elapsed,
+	}, nil
+}
+
+func (l *Limiter) count(ctx context.Context, key string) (int64, error) {
+	value, err := l.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// retryAfter returns the time until the estimate drops to allow one more
+// request
+func retryAfter(previous, current int64, limit Limit, elapsed time.Duration) time.Duration {
+	window := float64(limit.Window)
+	free := float64(limit.Requests - 1)
+
+	// The previous window still weighs too much, but the current one alone
+	// leaves room
+	if float64(current) <= free {
+		t := window*(1-(free-float64(current))/float64(previous)) - float64(elapsed)
+		if t < 0 {
+			t = 0
+		}
+		return time.Duration(t)
+	}
+
+	// Otherwise the current window has to end and then weigh less
+	return limit.Window - elapsed + time.Duration(window*(1-free/float64(current)))
+}
@@ -0,0 +1,166 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter() (*Limiter, *clock) {
+	// The start of a minute, so that windows of a minute start with it
+	c := &clock{now: time.Unix(6000, 0)}
+
+	limiter := New(newMemoryStore(c.Now), "rate_limit_")
+	limiter.now = c.Now
+
+	return limiter, c
+}
+
+func TestSlidingWindow(t *testing.T) {
+	limiter, c := newTestLimiter()
+	limit := Limit{Requests: 3, Window: time.Minute}
+
+	for i := int64(1); i <= limit.Requests; i++ {
+		result, err := limiter.Allow(context.Background(), "ip_1", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, limit.Requests-i, result.Remaining)
+		require.Equal(t, time.Minute, result.Reset)
+	}
+
+	result, err := limiter.Allow(context.Background(), "ip_1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 90*time.Second, result.Reset)
+
+	// Other keys are counted apart
+	result, err = limiter.Allow(context.Background(), "ip_2", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// In the next window the previous one still weighs too much, and the
+	// rejected request is counted as well
+	c.now = c.now.Add(79 * time.Second)
+
+	result, err = limiter.Allow(context.Background(), "ip_1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 26*time.Second, result.Reset)
+
+	c.now = c.now.Add(26 * time.Second)
+
+	result, err = limiter.Allow(context.Background(), "ip_1", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, int64(0), result.Remaining)
+	require.Equal(t, 15*time.Second, result.Reset)
+
+	// Once a whole window passes nothing is left
+	c.now = c.now.Add(2 * time.Minute)
+
+	result, err = limiter.Allow(context.Background(), "ip_1", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Equal(t, limit.Requests-1, result.Remaining)
+}
+
+// slowStore delays the replies to reads, so that concurrent requests
+// interleave as they would against a remote store
+type slowStore struct {
+	Store
+}
+
+func (s *slowStore) Get(ctx context.Context, key string) (string, error) {
+	value, err := s.Store.Get(ctx, key)
+	time.Sleep(10 * time.Millisecond)
+	return value, err
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	limiter, c := newTestLimiter()
+	limiter.store = &slowStore{Store: newMemoryStore(c.Now)}
+	limit := Limit{Requests: 5, Window: time.Minute}
+
+	const workers = 20
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int64
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			result, err := limiter.Allow(context.Background(), "ip_1", limit)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			if result.Allowed {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, limit.Requests, allowed)
+}
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules("100/1m", "POST /v1/auth/login=10/1m, get /v1/users=5/10s")
+	require.NoError(t, err)
+
+	limit, bucket := rules.Get("POST", "/v1/auth/login")
+	require.Equal(t, Limit{Requests: 10, Window: time.Minute}, limit)
+	require.Equal(t, "POST /v1/auth/login", bucket)
+
+	limit, bucket = rules.Get("GET", "/v1/users")
+	require.Equal(t, Limit{Requests: 5, Window: 10 * time.Second}, limit)
+	require.Equal(t, "GET /v1/users", bucket)
+
+	limit, bucket = rules.Get("GET", "/v1/properties")
+	require.Equal(t, Limit{Requests: 100, Window: time.Minute}, limit)
+	require.Equal(t, "default", bucket)
+
+	rules, err = LoadRules("", "")
+	require.NoError(t, err)
+
+	limit, _ = rules.Get("GET", "/v1/properties")
+	require.Zero(t, limit.Requests)
+
+	invalid := []struct {
+		defaultLimit string
+		routes       string
+	}{
+		{defaultLimit: "100"},
+		{defaultLimit: "0/1m"},
+		{defaultLimit: "100/0s"},
+		{defaultLimit: "many/1m"},
+		{routes: "POST /v1/auth/login"},
+		{routes: "/v1/auth/login=10/1m"},
+		{routes: "POST /v1/auth/login=10"},
+	}
+
+	for _, tc := range invalid {
+		_, err := LoadRules(tc.defaultLimit, tc.routes)
+		require.Error(t, err, "%q %q", tc.defaultLimit, tc.routes)
+	}
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// APIKeyPrefix starts every API key, so that leaked keys are easy to find
+const APIKeyPrefix = "bk_"
+
+// CreateAPIKey creates an API key of the form bk_<prefix>_<secret>. The
+// prefix identifies the key and may be shown; only the hash of the key
+// should be stored.
+func CreateAPIKey() (key string, prefix string, hash string, err error) {
+	p := make([]byte, 6)
+
+	_, err = rand.Read(p)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	s := make([]byte, 32)
+
+	_, err = rand.Read(s)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(p)
+	key = APIKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(s)
+
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// ParseAPIKey returns the prefix of the key
+func ParseAPIKey(key string) (string, error) {
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		return "", ErrInvalidToken
+	}
+
+	prefix, secret, ok := strings.Cut(strings.TrimPrefix(key, APIKeyPrefix), "_")
+	if !ok || secret == "" {
+		return "", ErrInvalidToken
+	}
+
+	_, err := hex.DecodeString(prefix)
+	if err != nil || len(prefix) != 12 {
+		return "", ErrInvalidToken
+	}
+
+	return prefix, nil
+}
+
+// HashAPIKey returns the hash of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	key, prefix, hash, err := CreateAPIKey()
+	require.NoError(t, err)
+	require.Equal(t, hash, HashAPIKey(key))
+
+	parsed, err := ParseAPIKey(key)
+	require.NoError(t, err)
+	require.Equal(t, prefix, parsed)
+
+	other, _, _, err := CreateAPIKey()
+	require.NoError(t, err)
+	require.NotEqual(t, key, other)
+
+	for _, invalid := range []string{"", "bk_", "bk_" + prefix, "bk_" + prefix + "_", "xx_" + prefix + "_secret", "bk_nothex00000_secret"} {
+		_, err = ParseAPIKey(invalid)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
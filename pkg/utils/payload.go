@@ -13,14 +13,30 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// Scopes of tokens which only grant a single step of a flow. Tokens without a
+// scope grant full access.
+const (
+	ScopeMFAPending    = "mfa_pending"
+	ScopeMFAEnroll     = "mfa_enroll"
+	ScopePasswordReset = "password_reset"
+	ScopeMagicLink     = "magic_link"
+	// ScopeAPIKey is the scope of payloads of API keys, which are limited to
+	// the permissions of the key
+	ScopeAPIKey = "api_key"
+)
+
 // Payload contains the payload data of the token
 type Payload struct {
 	ID        uuid.UUID `json:"id"`
 	UserID    int64     `json:"user_id"`
 	Email     string    `json:"email"`
 	UserType  string    `json:"type"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiredAt time.Time `json:"expired_at"`
+	SessionID string    `json:"session_id,omitempty"`
+	Scope     string    `json:"scope,omitempty"`
+	// Permissions limit payloads of API keys
+	Permissions []string  `json:"permissions,omitempty"`
+	IssuedAt    time.Time `json:"issued_at"`
+	ExpiredAt   time.Time `json:"expired_at"`
 }
 
 // NewPayload creates a new token payload
@@ -35,6 +51,8 @@ func NewPayload(params *TokenParams) (*Payload, error) {
 		UserID:    params.UserID,
 		Email:     params.Email,
 		UserType:  params.UserType,
+		SessionID: params.SessionID,
+		Scope:     params.Scope,
 		IssuedAt:  time.Now(),
 		ExpiredAt: time.Now().Add(params.Duration),
 	}
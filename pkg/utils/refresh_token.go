@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CreateRefreshToken creates an opaque refresh token of the session. Only the
+// returned hash should be stored.
+func CreateRefreshToken(sessionID string) (token string, hash string, err error) {
+	b := make([]byte, 32)
+
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", "", err
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(b)
+
+	return sessionID + "." + secret, HashRefreshToken(secret), nil
+}
+
+// ParseRefreshToken splits the token into its session ID and secret
+func ParseRefreshToken(token string) (sessionID string, secret string, err error) {
+	sessionID, secret, ok := strings.Cut(token, ".")
+	if !ok || secret == "" {
+		return "", "", ErrInvalidToken
+	}
+
+	_, err = uuid.Parse(sessionID)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+
+	return sessionID, secret, nil
+}
+
+// HashRefreshToken returns the hash of the secret of a refresh token
+func HashRefreshToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
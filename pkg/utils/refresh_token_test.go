@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshToken(t *testing.T) {
+	sessionID := uuid.NewString()
+
+	token, hash, err := CreateRefreshToken(sessionID)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	id, secret, err := ParseRefreshToken(token)
+	require.NoError(t, err)
+	require.Equal(t, sessionID, id)
+	require.Equal(t, hash, HashRefreshToken(secret))
+
+	other, _, err := CreateRefreshToken(sessionID)
+	require.NoError(t, err)
+	require.NotEqual(t, token, other)
+
+	for _, invalid := range []string{"", sessionID, sessionID + ".", "not-a-uuid.secret"} {
+		_, _, err = ParseRefreshToken(invalid)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	}
+}
@@ -5,40 +5,33 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt"
-	"github.com/ibrat-muslim/booking-service/config"
+	"github.com/ibrat-muslim/booking-service/pkg/keyring"
 )
 
 type TokenParams struct {
-	UserID   int64
-	Username string
-	Email    string
-	UserType string
-	Duration time.Duration
+	UserID    int64
+	Username  string
+	Email     string
+	UserType  string
+	SessionID string
+	Scope     string
+	Duration  time.Duration
 }
 
-// CreateToken creates a new token
-func CreateToken(cfg *config.Config, params *TokenParams) (string, *Payload, error) {
+// CreateToken creates a new token signed with the signing key of the key ring
+func CreateToken(keys *keyring.KeyRing, params *TokenParams) (string, *Payload, error) {
 	payload, err := NewPayload(params)
 	if err != nil {
 		return "", payload, err
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
-	token, err := jwtToken.SignedString([]byte(cfg.AuthSecretKey))
+	token, err := keys.Sign(payload)
 	return token, payload, err
 }
 
 // VerifyToken checks if the token is valid or not
-func VerifyToken(cfg *config.Config, token string) (*Payload, error) {
-	keyFunc := func(token *jwt.Token) (interface{}, error) {
-		_, ok := token.Method.(*jwt.SigningMethodHMAC)
-		if !ok {
-			return nil, ErrInvalidToken
-		}
-		return []byte(cfg.AuthSecretKey), nil
-	}
-
-	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+func VerifyToken(keys *keyring.KeyRing, token string) (*Payload, error) {
+	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keys.Keyfunc)
 	if err != nil {
 		verr, ok := err.(*jwt.ValidationError)
 		if ok && errors.Is(verr.Inner, ErrExpiredToken) {
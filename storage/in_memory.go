@@ -2,38 +2,140 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// ErrKeyNotFound is returned by Get when the key does not exist or has expired
+var ErrKeyNotFound = errors.New("key not found")
+
 type InMemoryStorageI interface {
-	Set(key, value string, exp time.Duration) error
-	Get(key string) (string, error)
+	Set(ctx context.Context, key, value string, exp time.Duration) error
+	// SetNX sets the key only if it does not exist. It reports whether the
+	// key was set.
+	SetNX(ctx context.Context, key, value string, exp time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	// GetDel returns the value of the key and deletes it at once, so that
+	// only one caller can get it
+	GetDel(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, key string) error
+	// Incr increments the integer value of the key and returns it. A new key
+	// starts at 0 and expires after exp.
+	Incr(ctx context.Context, key string, exp time.Duration) (int64, error)
+	// TTL returns the remaining time to live of the key, or 0 if it has no
+	// expiry. It returns ErrKeyNotFound if the key does not exist.
+	TTL(ctx context.Context, key string) (time.Duration, error)
 }
 
+// incrScript sets the expiry only when the key is created, so that repeated
+// increments do not extend it. A zero expiry leaves the key without one, as
+// PEXPIRE with 0 would delete it.
+var incrScript = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
 type storageRedis struct {
-	client *redis.Client
+	client  *redis.Client
+	timeout time.Duration
 }
 
-func NewInMemoryStorage(rdb *redis.Client) InMemoryStorageI {
+// NewInMemoryStorage returns the Redis backend. Every command is cancelled
+// after timeout, a zero timeout leaves only the deadline of the caller.
+func NewInMemoryStorage(rdb *redis.Client, timeout time.Duration) InMemoryStorageI {
 	return &storageRedis{
-		client: rdb,
+		client:  rdb,
+		timeout: timeout,
+	}
+}
+
+func (r *storageRedis) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, r.timeout)
 }
 
-func (r *storageRedis) Set(key, value string, exp time.Duration) error {
-	err := r.client.Set(context.Background(), key, value, exp).Err()
+func (r *storageRedis) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := r.client.Set(ctx, key, value, exp).Err()
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (r *storageRedis) Get(key string) (string, error) {
-	val, err := r.client.Get(context.Background(), key).Result()
+func (r *storageRedis) SetNX(ctx context.Context, key, value string, exp time.Duration) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.client.SetNX(ctx, key, value, exp).Result()
+}
+
+func (r *storageRedis) Get(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	val, err := r.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrKeyNotFound
+	}
 	if err != nil {
 		return "", err
 	}
 	return val, nil
+}
+
+func (r *storageRedis) GetDel(ctx context.Context, key string) (string, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	val, err := r.client.GetDel(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrKeyNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return val, nil
+}
+
+func (r *storageRedis) Delete(ctx context.Context, key string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.client.Del(ctx, key).Err()
+}
+
+func (r *storageRedis) Incr(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return incrScript.Run(ctx, r.client, []string{key}, exp.Milliseconds()).Int64()
+}
+
+func (r *storageRedis) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	ttl, err := r.client.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	switch ttl {
+	case -2:
+		return 0, ErrKeyNotFound
+	case -1:
+		return 0, nil
+	}
+
+	return ttl, nil
 }
\ No newline at end of file
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultJanitorInterval is how often the local storage removes expired keys
+const DefaultJanitorInterval = time.Minute
+
+type localEntry struct {
+	value string
+	// expiresAt is zero for keys without expiry
+	expiresAt time.Time
+}
+
+func (e *localEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// storageLocal keeps the keys in process. Unlike Redis, the keys are lost on
+// restart and are not shared between instances, so it is meant for tests and
+// local runs.
+type storageLocal struct {
+	mu      sync.Mutex
+	entries map[string]*localEntry
+	now     func() time.Time
+}
+
+// NewLocalInMemoryStorage creates an in-process storage. A janitor removes
+// expired keys every interval until ctx is done; expired keys are never
+// returned in between.
+func NewLocalInMemoryStorage(ctx context.Context, interval time.Duration) InMemoryStorageI {
+	s := newStorageLocal(time.Now)
+
+	if interval <= 0 {
+		interval = DefaultJanitorInterval
+	}
+
+	go s.janitor(ctx, interval)
+
+	return s
+}
+
+func newStorageLocal(now func() time.Time) *storageLocal {
+	return &storageLocal{
+		entries: make(map[string]*localEntry),
+		now:     now,
+	}
+}
+
+func (s *storageLocal) janitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.deleteExpired()
+		}
+	}
+}
+
+func (s *storageLocal) deleteExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	for key, e := range s.entries {
+		if e.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// get returns the entry of the key, or nil if it does not exist or has
+// expired. The caller must hold the lock.
+func (s *storageLocal) get(key string) *localEntry {
+	e, ok := s.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if e.expired(s.now()) {
+		delete(s.entries, key)
+		return nil
+	}
+
+	return e
+}
+
+// newEntry creates an entry which expires after exp, or never if exp is 0
+// like in Redis. The caller must hold the lock.
+func (s *storageLocal) newEntry(value string, exp time.Duration) *localEntry {
+	e := localEntry{
+		value: value,
+	}
+
+	if exp > 0 {
+		e.expiresAt = s.now().Add(exp)
+	}
+
+	return &e
+}
+
+func (s *storageLocal) Set(ctx context.Context, key, value string, exp time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = s.newEntry(value, exp)
+
+	return nil
+}
+
+func (s *storageLocal) SetNX(ctx context.Context, key, value string, exp time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.get(key) != nil {
+		return false, nil
+	}
+
+	s.entries[key] = s.newEntry(value, exp)
+
+	return true, nil
+}
+
+func (s *storageLocal) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.get(key)
+	if e == nil {
+		return "", ErrKeyNotFound
+	}
+
+	return e.value, nil
+}
+
+func (s *storageLocal) GetDel(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.get(key)
+	if e == nil {
+		return "", ErrKeyNotFound
+	}
+
+	delete(s.entries, key)
+
+	return e.value, nil
+}
+
+func (s *storageLocal) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+
+	return nil
+}
+
+func (s *storageLocal) Incr(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.get(key)
+	if e == nil {
+		e = s.newEntry("0", exp)
+		s.entries[key] = e
+	}
+
+	n, err := strconv.ParseInt(e.value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	n++
+	e.value = strconv.FormatInt(n, 10)
+
+	return n, nil
+}
+
+func (s *storageLocal) TTL(ctx context.Context, key string) (time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := s.get(key)
+	if e == nil {
+		return 0, ErrKeyNotFound
+	}
+
+	if e.expiresAt.IsZero() {
+		return 0, nil
+	}
+
+	return e.expiresAt.Sub(s.now()), nil
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// clock is a manual clock for the local storage
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestLocalSetGet(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := newStorageLocal(c.Now)
+
+	_, err := s.Get(context.Background(), "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	err = s.Set(context.Background(), "key", "value", time.Minute)
+	require.NoError(t, err)
+
+	err = s.Set(context.Background(), "forever", "value", 0)
+	require.NoError(t, err)
+
+	value, err := s.Get(context.Background(), "key")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+
+	c.Advance(time.Minute)
+
+	_, err = s.Get(context.Background(), "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	value, err = s.Get(context.Background(), "forever")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+
+	err = s.Delete(context.Background(), "forever")
+	require.NoError(t, err)
+
+	_, err = s.Get(context.Background(), "forever")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestLocalSetNX(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := newStorageLocal(c.Now)
+
+	ok, err := s.SetNX(context.Background(), "key", "first", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = s.SetNX(context.Background(), "key", "second", time.Minute)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	value, err := s.Get(context.Background(), "key")
+	require.NoError(t, err)
+	require.Equal(t, "first", value)
+
+	// An expired key can be set again
+	c.Advance(time.Minute)
+
+	ok, err = s.SetNX(context.Background(), "key", "third", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+func TestLocalGetDel(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := newStorageLocal(c.Now)
+
+	err := s.Set(context.Background(), "key", "value", time.Minute)
+	require.NoError(t, err)
+
+	value, err := s.GetDel(context.Background(), "key")
+	require.NoError(t, err)
+	require.Equal(t, "value", value)
+
+	// The key can be taken only once
+	_, err = s.GetDel(context.Background(), "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	err = s.Set(context.Background(), "key", "value", time.Minute)
+	require.NoError(t, err)
+
+	c.Advance(time.Minute)
+
+	_, err = s.GetDel(context.Background(), "key")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestLocalIncrTTL(t *testing.T) {
+	c := &clock{now: time.Now()}
+	s := newStorageLocal(c.Now)
+
+	_, err := s.TTL(context.Background(), "counter")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	n, err := s.Incr(context.Background(), "counter", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	c.Advance(20 * time.Second)
+
+	// Increments do not extend the expiry
+	n, err = s.Incr(context.Background(), "counter", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	ttl, err := s.TTL(context.Background(), "counter")
+	require.NoError(t, err)
+	require.Equal(t, 40*time.Second, ttl)
+
+	c.Advance(40 * time.Second)
+
+	n, err = s.Incr(context.Background(), "counter", time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	err = s.Set(context.Background(), "text", "value", 0)
+	require.NoError(t, err)
+
+	ttl, err = s.TTL(context.Background(), "text")
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+
+	_, err = s.Incr(context.Background(), "text", time.Minute)
+	require.Error(t, err)
+}
+
+func TestLocalJanitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewLocalInMemoryStorage(ctx, 10*time.Millisecond).(*storageLocal)
+
+	err := s.Set(ctx, "key", "value", time.Millisecond)
+	require.NoError(t, err)
+
+	err = s.Set(ctx, "forever", "value", 0)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		_, ok := s.entries["key"]
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = s.Get(ctx, "forever")
+	require.NoError(t, err)
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/ibrat-muslim/booking-service/config"
+	"github.com/stretchr/testify/require"
+)
+
+// testInMemoryStorage checks the behaviour both backends must share. Keys are
+// prefixed so that a shared Redis is left as it was found.
+func testInMemoryStorage(t *testing.T, s InMemoryStorageI) {
+	ctx := context.Background()
+	prefix := "storage_test_" + time.Now().Format(time.RFC3339Nano) + "_"
+
+	t.Cleanup(func() {
+		for _, key := range []string{"key", "nx", "counter", "forever"} {
+			_ = s.Delete(ctx, prefix+key)
+		}
+	})
+
+	t.Run("GetDel", func(t *testing.T) {
+		err := s.Set(ctx, prefix+"key", "value", time.Minute)
+		require.NoError(t, err)
+
+		value, err := s.GetDel(ctx, prefix+"key")
+		require.NoError(t, err)
+		require.Equal(t, "value", value)
+
+		_, err = s.GetDel(ctx, prefix+"key")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+
+		_, err = s.Get(ctx, prefix+"key")
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("SetNX", func(t *testing.T) {
+		ok, err := s.SetNX(ctx, prefix+"nx", "first", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		ok, err = s.SetNX(ctx, prefix+"nx", "second", time.Minute)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("Incr", func(t *testing.T) {
+		n, err := s.Incr(ctx, prefix+"counter", time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		n, err = s.Incr(ctx, prefix+"counter", time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		ttl, err := s.TTL(ctx, prefix+"counter")
+		require.NoError(t, err)
+		require.True(t, ttl > 0 && ttl <= time.Minute, ttl)
+	})
+
+	t.Run("IncrWithoutExpiry", func(t *testing.T) {
+		n, err := s.Incr(ctx, prefix+"forever", 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+
+		// The key is kept with no expiry rather than expiring at once
+		ttl, err := s.TTL(ctx, prefix+"forever")
+		require.NoError(t, err)
+		require.Zero(t, ttl)
+
+		n, err = s.Incr(ctx, prefix+"forever", 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+	})
+}
+
+func TestLocalInMemoryStorage(t *testing.T) {
+	testInMemoryStorage(t, newStorageLocal(time.Now))
+}
+
+// TestRedisInMemoryStorage runs against the Redis of the config and is
+// skipped when it cannot be reached
+func TestRedisInMemoryStorage(t *testing.T) {
+	cfg := config.Load("./..")
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr: cfg.Redis.Addr,
+	})
+	defer rdb.Close()
+
+	err := rdb.Ping(context.Background()).Err()
+	if err != nil {
+		t.Skipf("redis is not available: %v", err)
+	}
+
+	testInMemoryStorage(t, NewInMemoryStorage(rdb, cfg.Redis.Timeout))
+}
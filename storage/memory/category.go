@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type categoryRepo struct {
+	db *DB
+}
+
+func NewCategory(db *DB) repo.CategoryStorageI {
+	return &categoryRepo{
+		db: db,
+	}
+}
+
+func (cr *categoryRepo) Create(ctx context.Context, category *repo.Category) (*repo.Category, error) {
+	cr.db.mu.Lock()
+	defer cr.db.mu.Unlock()
+
+	category.ID = cr.db.nextID("categories")
+	category.CreatedAt = time.Now()
+
+	row := *category
+	cr.db.categories[row.ID] = &row
+
+	return category, nil
+}
+
+func (cr *categoryRepo) Get(ctx context.Context, id int64) (*repo.Category, error) {
+	cr.db.mu.RLock()
+	defer cr.db.mu.RUnlock()
+
+	row, ok := cr.db.categories[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	result := *row
+	return &result, nil
+}
+
+func (cr *categoryRepo) GetAll(ctx context.Context, params *repo.GetCategoriesParams) (*repo.GetCategoriesResult, error) {
+	cr.db.mu.RLock()
+	defer cr.db.mu.RUnlock()
+
+	categories := make([]*repo.Category, 0)
+
+	for _, row := range cr.db.categories {
+		if params.Search != "" && !contains(params.Search, row.Title) {
+			continue
+		}
+
+		category := *row
+		categories = append(categories, &category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return newer(categories[i].CreatedAt, categories[i].ID, categories[j].CreatedAt, categories[j].ID)
+	})
+
+	start, end := page(len(categories), params.Page, params.Limit)
+
+	return &repo.GetCategoriesResult{
+		Categories: categories[start:end],
+		Count:      int32(len(categories)),
+	}, nil
+}
+
+func (cr *categoryRepo) Update(ctx context.Context, category *repo.Category) error {
+	cr.db.mu.Lock()
+	defer cr.db.mu.Unlock()
+
+	row, ok := cr.db.categories[category.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	row.Title = category.Title
+
+	return nil
+}
+
+func (cr *categoryRepo) Delete(ctx context.Context, id int64) error {
+	cr.db.mu.Lock()
+	defer cr.db.mu.Unlock()
+
+	if _, ok := cr.db.categories[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(cr.db.categories, id)
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type commentRepo struct {
+	db *DB
+}
+
+func NewComment(db *DB) repo.CommentStorageI {
+	return &commentRepo{
+		db: db,
+	}
+}
+
+func (cmr *commentRepo) Create(ctx context.Context, comment *repo.Comment) (*repo.Comment, error) {
+	cmr.db.mu.Lock()
+	defer cmr.db.mu.Unlock()
+
+	comment.ID = cmr.db.nextID("comments")
+	comment.CreatedAt = time.Now()
+
+	row := *comment
+	row.UpdatedAt = nil
+	cmr.db.comments[row.ID] = &row
+
+	return comment, nil
+}
+
+// GetAll lists comments with their authors. Like the join of the postgres
+// repository, comments of missing users are left out.
+func (cmr *commentRepo) GetAll(ctx context.Context, params *repo.GetCommentsParams) (*repo.GetCommentsResult, error) {
+	cmr.db.mu.RLock()
+	defer cmr.db.mu.RUnlock()
+
+	comments := make([]*repo.Comment, 0)
+
+	for _, row := range cmr.db.comments {
+		if params.PostID != 0 && row.PostID != params.PostID {
+			continue
+		}
+
+		if params.UserID != 0 && row.UserID != params.UserID {
+			continue
+		}
+
+		user, ok := cmr.db.users[row.UserID]
+		if !ok {
+			continue
+		}
+
+		comment := *row
+		comment.User.FirstName = user.FirstName
+		comment.User.LastName = user.LastName
+		comment.User.Email = user.Email
+		comment.User.ProfileImageUrl = user.ProfileImageUrl
+
+		comments = append(comments, &comment)
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		return newer(comments[i].CreatedAt, comments[i].ID, comments[j].CreatedAt, comments[j].ID)
+	})
+
+	start, end := page(len(comments), params.Page, params.Limit)
+
+	return &repo.GetCommentsResult{
+		Comments: comments[start:end],
+		Count:    int32(len(comments)),
+	}, nil
+}
+
+func (cmr *commentRepo) GetAuthorID(ctx context.Context, id int64) (int64, error) {
+	cmr.db.mu.RLock()
+	defer cmr.db.mu.RUnlock()
+
+	row, ok := cmr.db.comments[id]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+
+	return row.UserID, nil
+}
+
+func (cmr *commentRepo) Update(ctx context.Context, comment *repo.Comment) error {
+	cmr.db.mu.Lock()
+	defer cmr.db.mu.Unlock()
+
+	row, ok := cmr.db.comments[comment.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	row.Description = comment.Description
+	row.UpdatedAt = comment.UpdatedAt
+
+	return nil
+}
+
+func (cmr *commentRepo) Delete(ctx context.Context, id int64) error {
+	cmr.db.mu.Lock()
+	defer cmr.db.mu.Unlock()
+
+	if _, ok := cmr.db.comments[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(cmr.db.comments, id)
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type likeRepo struct {
+	db *DB
+}
+
+func NewLike(db *DB) repo.LikeStorageI {
+	return &likeRepo{
+		db: db,
+	}
+}
+
+// CreateOrUpdate toggles the like of the user: a new like is created, the
+// same like again removes it and the opposite one replaces it
+func (lr *likeRepo) CreateOrUpdate(ctx context.Context, like *repo.Like) error {
+	lr.db.mu.Lock()
+	defer lr.db.mu.Unlock()
+
+	row := lr.find(like.PostID, like.UserID)
+
+	switch {
+	case row == nil:
+		id := lr.db.nextID("likes")
+		lr.db.likes[id] = &repo.Like{
+			ID:     id,
+			PostID: like.PostID,
+			UserID: like.UserID,
+			Status: like.Status,
+		}
+	case row.Status == like.Status:
+		delete(lr.db.likes, row.ID)
+	default:
+		row.Status = like.Status
+	}
+
+	return nil
+}
+
+func (lr *likeRepo) Get(ctx context.Context, postID, userID int64) (*repo.Like, error) {
+	lr.db.mu.RLock()
+	defer lr.db.mu.RUnlock()
+
+	row := lr.find(postID, userID)
+	if row == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	result := *row
+	return &result, nil
+}
+
+func (lr *likeRepo) GetLikesDislikesCount(ctx context.Context, postID int64) (*repo.LikesDislikesCountsResult, error) {
+	lr.db.mu.RLock()
+	defer lr.db.mu.RUnlock()
+
+	var result repo.LikesDislikesCountsResult
+
+	for _, row := range lr.db.likes {
+		if row.PostID != postID {
+			continue
+		}
+
+		if row.Status {
+			result.LikesCount++
+		} else {
+			result.DislikesCount++
+		}
+	}
+
+	return &result, nil
+}
+
+// find returns the like of the user for the post, or nil. The caller must
+// hold the lock.
+func (lr *likeRepo) find(postID, userID int64) *repo.Like {
+	for _, row := range lr.db.likes {
+		if row.PostID == postID && row.UserID == userID {
+			return row
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package memory implements the user, category, post, comment and like
+// repositories in memory, for tests and demos which run without a database.
+// The repositories behave like those of storage/postgres: missing rows are
+// reported with sql.ErrNoRows.
+package memory
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+// ErrDuplicateEmail is returned when a user is created or updated with the
+// email of another user
+var ErrDuplicateEmail = errors.New("email already exists")
+
+// DB holds the rows of the repositories. Repositories sharing a DB see each
+// other's rows, e.g. comments are listed with their authors.
+type DB struct {
+	mu sync.RWMutex
+
+	users      map[int64]*repo.User
+	categories map[int64]*repo.Category
+	posts      map[int64]*repo.Post
+	comments   map[int64]*repo.Comment
+	likes      map[int64]*repo.Like
+
+	// lastID holds the last ID given out for every table
+	lastID map[string]int64
+}
+
+func NewDB() *DB {
+	return &DB{
+		users:      make(map[int64]*repo.User),
+		categories: make(map[int64]*repo.Category),
+		posts:      make(map[int64]*repo.Post),
+		comments:   make(map[int64]*repo.Comment),
+		likes:      make(map[int64]*repo.Like),
+		lastID:     make(map[string]int64),
+	}
+}
+
+func (db *DB) nextID(table string) int64 {
+	db.lastID[table]++
+	return db.lastID[table]
+}
+
+// Storage holds the repositories of a DB
+type Storage struct {
+	userRepo     repo.UserStorageI
+	categoryRepo repo.CategoryStorageI
+	postRepo     repo.PostStorageI
+	commentRepo  repo.CommentStorageI
+	likeRepo     repo.LikeStorageI
+}
+
+// New creates the repositories of a new, empty DB
+func New() *Storage {
+	db := NewDB()
+
+	return &Storage{
+		userRepo:     NewUser(db),
+		categoryRepo: NewCategory(db),
+		postRepo:     NewPost(db),
+		commentRepo:  NewComment(db),
+		likeRepo:     NewLike(db),
+	}
+}
+
+func (s *Storage) User() repo.UserStorageI {
+	return s.userRepo
+}
+
+func (s *Storage) Category() repo.CategoryStorageI {
+	return s.categoryRepo
+}
+
+func (s *Storage) Post() repo.PostStorageI {
+	return s.postRepo
+}
+
+func (s *Storage) Comment() repo.CommentStorageI {
+	return s.commentRepo
+}
+
+func (s *Storage) Like() repo.LikeStorageI {
+	return s.likeRepo
+}
+
+// contains reports whether any of the values contains search, ignoring case
+// like ILIKE
+func contains(search string, values ...string) bool {
+	search = strings.ToLower(search)
+
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), search) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newer reports whether row a was created after row b. Rows created at the
+// same time are ordered by ID.
+func newer(aCreatedAt time.Time, aID int64, bCreatedAt time.Time, bID int64) bool {
+	if aCreatedAt.Equal(bCreatedAt) {
+		return aID > bID
+	}
+	return aCreatedAt.After(bCreatedAt)
+}
+
+// page returns the bounds of the page, counting from 1, within n rows
+func page(n int, page, limit int32) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 0 {
+		limit = 0
+	}
+
+	start := int(page-1) * int(limit)
+	if start > n {
+		start = n
+	}
+
+	end := start + int(limit)
+	if end > n {
+		end = n
+	}
+
+	return start, end
+}
+
+func optional(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
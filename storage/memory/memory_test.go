@@ -0,0 +1,12 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/memory"
+	"github.com/ibrat-muslim/booking-service/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, memory.New())
+}
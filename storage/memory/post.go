@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type postRepo struct {
+	db *DB
+}
+
+func NewPost(db *DB) repo.PostStorageI {
+	return &postRepo{
+		db: db,
+	}
+}
+
+func (pr *postRepo) Create(ctx context.Context, post *repo.Post) (*repo.Post, error) {
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	post.ID = pr.db.nextID("posts")
+	post.CreatedAt = time.Now()
+
+	row := *post
+	row.UpdatedAt = nil
+	row.ViewsCount = 0
+	pr.db.posts[row.ID] = &row
+
+	return post, nil
+}
+
+// Get counts a view of the post, like the postgres repository
+func (pr *postRepo) Get(ctx context.Context, id int64) (*repo.Post, error) {
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	row, ok := pr.db.posts[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	row.ViewsCount++
+
+	result := *row
+	return &result, nil
+}
+
+func (pr *postRepo) GetAuthorID(ctx context.Context, id int64) (int64, error) {
+	pr.db.mu.RLock()
+	defer pr.db.mu.RUnlock()
+
+	row, ok := pr.db.posts[id]
+	if !ok {
+		return 0, sql.ErrNoRows
+	}
+
+	return row.UserID, nil
+}
+
+func (pr *postRepo) GetAll(ctx context.Context, params *repo.GetPostsParams) (*repo.GetPostsResult, error) {
+	pr.db.mu.RLock()
+	defer pr.db.mu.RUnlock()
+
+	posts := make([]*repo.Post, 0)
+
+	for _, row := range pr.db.posts {
+		if params.Search != "" && !contains(params.Search, row.Title) {
+			continue
+		}
+
+		if params.UserID != 0 && row.UserID != params.UserID {
+			continue
+		}
+
+		if params.CategoryID != 0 && row.CategoryID != params.CategoryID {
+			continue
+		}
+
+		post := *row
+		posts = append(posts, &post)
+	}
+
+	asc := strings.EqualFold(params.SortByDate, "asc")
+
+	sort.Slice(posts, func(i, j int) bool {
+		if asc {
+			return newer(posts[j].CreatedAt, posts[j].ID, posts[i].CreatedAt, posts[i].ID)
+		}
+		return newer(posts[i].CreatedAt, posts[i].ID, posts[j].CreatedAt, posts[j].ID)
+	})
+
+	start, end := page(len(posts), params.Page, params.Limit)
+
+	return &repo.GetPostsResult{
+		Posts: posts[start:end],
+		Count: int32(len(posts)),
+	}, nil
+}
+
+func (pr *postRepo) Update(ctx context.Context, post *repo.Post) error {
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	row, ok := pr.db.posts[post.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	row.Title = post.Title
+	row.Description = post.Description
+	row.ImageUrl = post.ImageUrl
+	row.CategoryID = post.CategoryID
+	row.UpdatedAt = post.UpdatedAt
+
+	return nil
+}
+
+func (pr *postRepo) Delete(ctx context.Context, id int64) error {
+	pr.db.mu.Lock()
+	defer pr.db.mu.Unlock()
+
+	if _, ok := pr.db.posts[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(pr.db.posts, id)
+
+	return nil
+}
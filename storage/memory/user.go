@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type userRepo struct {
+	db *DB
+}
+
+func NewUser(db *DB) repo.UserStorageI {
+	return &userRepo{
+		db: db,
+	}
+}
+
+func (ur *userRepo) Create(ctx context.Context, user *repo.User) (*repo.User, error) {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	if ur.emailTaken(user.Email, 0) {
+		return nil, ErrDuplicateEmail
+	}
+
+	user.ID = ur.db.nextID("users")
+	user.CreatedAt = time.Now()
+	user.BannedAt = nil
+
+	row := *user
+	ur.db.users[row.ID] = &row
+
+	return user, nil
+}
+
+func (ur *userRepo) Get(ctx context.Context, id int64) (*repo.User, error) {
+	ur.db.mu.RLock()
+	defer ur.db.mu.RUnlock()
+
+	row, ok := ur.db.users[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	result := *row
+	return &result, nil
+}
+
+func (ur *userRepo) GetByEmail(ctx context.Context, email string) (*repo.User, error) {
+	ur.db.mu.RLock()
+	defer ur.db.mu.RUnlock()
+
+	for _, row := range ur.db.users {
+		if row.Email == email {
+			result := *row
+			return &result, nil
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+func (ur *userRepo) GetAll(ctx context.Context, params *repo.GetUsersParams) (*repo.GetUsersResult, error) {
+	ur.db.mu.RLock()
+	defer ur.db.mu.RUnlock()
+
+	users := make([]*repo.User, 0)
+
+	for _, row := range ur.db.users {
+		if params.Search != "" && !contains(params.Search, row.FirstName, row.LastName, optional(row.PhoneNumber), row.Email) {
+			continue
+		}
+
+		user := *row
+		users = append(users, &user)
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return newer(users[i].CreatedAt, users[i].ID, users[j].CreatedAt, users[j].ID)
+	})
+
+	start, end := page(len(users), params.Page, params.Limit)
+
+	return &repo.GetUsersResult{
+		Users: users[start:end],
+		Count: int32(len(users)),
+	}, nil
+}
+
+func (ur *userRepo) Update(ctx context.Context, user *repo.User) error {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	row, ok := ur.db.users[user.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	if ur.emailTaken(user.Email, user.ID) {
+		return ErrDuplicateEmail
+	}
+
+	row.FirstName = user.FirstName
+	row.LastName = user.LastName
+	row.DateOfBirth = user.DateOfBirth
+	row.Email = user.Email
+	row.PhoneNumber = user.PhoneNumber
+	row.Gender = user.Gender
+	row.Password = user.Password
+	row.ProfileImageUrl = user.ProfileImageUrl
+	row.Address = user.Address
+	row.Type = user.Type
+
+	return nil
+}
+
+func (ur *userRepo) Delete(ctx context.Context, id int64) error {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	if _, ok := ur.db.users[id]; !ok {
+		return sql.ErrNoRows
+	}
+
+	delete(ur.db.users, id)
+
+	return nil
+}
+
+func (ur *userRepo) UpdatePassword(ctx context.Context, req *repo.UpdatePassword) error {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	if row, ok := ur.db.users[req.UserID]; ok {
+		row.Password = req.Password
+	}
+
+	return nil
+}
+
+func (ur *userRepo) Ban(ctx context.Context, id int64) error {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	row, ok := ur.db.users[id]
+	if !ok || row.BannedAt != nil {
+		return sql.ErrNoRows
+	}
+
+	now := time.Now()
+	row.BannedAt = &now
+
+	return nil
+}
+
+func (ur *userRepo) Unban(ctx context.Context, id int64) error {
+	ur.db.mu.Lock()
+	defer ur.db.mu.Unlock()
+
+	row, ok := ur.db.users[id]
+	if !ok || row.BannedAt == nil {
+		return sql.ErrNoRows
+	}
+
+	row.BannedAt = nil
+
+	return nil
+}
+
+// emailTaken reports whether a user other than id has the email. The caller
+// must hold the lock.
+func (ur *userRepo) emailTaken(email string, id int64) bool {
+	for _, row := range ur.db.users {
+		if row.Email == email && row.ID != id {
+			return true
+		}
+	}
+
+	return false
+}
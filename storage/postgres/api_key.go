@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type apiKeyRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewAPIKey(db DB, timeout time.Duration) repo.APIKeyStorageI {
+	return &apiKeyRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+const apiKeyColumns = `
+	id,
+	user_id,
+	name,
+	prefix,
+	key_hash,
+	scopes,
+	expires_at,
+	last_used_at,
+	created_at
+`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*repo.APIKey, error) {
+	var key repo.APIKey
+
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		pq.Array(&key.Scopes),
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+func (ar *apiKeyRepo) Create(ctx context.Context, key *repo.APIKey) (*repo.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, ar.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO api_keys (
+			user_id,
+			name,
+			prefix,
+			key_hash,
+			scopes,
+			expires_at
+		) VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	row := ar.db.QueryRowContext(ctx,
+		query,
+		key.UserID,
+		key.Name,
+		key.Prefix,
+		key.KeyHash,
+		pq.Array(key.Scopes),
+		key.ExpiresAt,
+	)
+
+	err := row.Scan(
+		&key.ID,
+		&key.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (ar *apiKeyRepo) GetByPrefix(ctx context.Context, prefix string) (*repo.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, ar.timeout)
+	defer cancel()
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+
+	return scanAPIKey(ar.db.QueryRowContext(ctx, query, prefix))
+}
+
+func (ar *apiKeyRepo) GetAll(ctx context.Context, userID int64) ([]*repo.APIKey, error) {
+	ctx, cancel := withTimeout(ctx, ar.timeout)
+	defer cancel()
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := ar.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*repo.APIKey, 0)
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, key)
+	}
+
+	return result, rows.Err()
+}
+
+func (ar *apiKeyRepo) Touch(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, ar.timeout)
+	defer cancel()
+
+	query := `UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	_, err := ar.db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+func (ar *apiKeyRepo) Delete(ctx context.Context, id, userID int64) error {
+	ctx, cancel := withTimeout(ctx, ar.timeout)
+	defer cancel()
+
+	query := `DELETE FROM api_keys WHERE id = $1 AND user_id = $2`
+
+	result, err := ar.db.ExecContext(ctx, query, id, userID)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (ar *apiKeyRepo) DeleteAll(ctx context.Context, userID int64) error {
+	ctx, cancel := withTimeout(ctx, ar.timeout)
+	defer cancel()
+
+	query := `DELETE FROM api_keys WHERE user_id = $1`
+
+	_, err := ar.db.ExecContext(ctx, query, userID)
+
+	return err
+}
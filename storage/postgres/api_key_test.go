@@ -0,0 +1,81 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/pkg/utils"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKey(t *testing.T) {
+	user := createUser(t)
+
+	_, prefix, hash, err := utils.CreateAPIKey()
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Hour)
+
+	key, err := strg.APIKey().Create(ctx, &repo.APIKey{
+		UserID:    user.ID,
+		Name:      "channel manager",
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    []string{"booking:read", "booking:manage"},
+		ExpiresAt: &expiresAt,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, key.ID)
+
+	result, err := strg.APIKey().GetByPrefix(ctx, prefix)
+	require.NoError(t, err)
+	require.Equal(t, hash, result.KeyHash)
+	require.Equal(t, key.Scopes, result.Scopes)
+	require.Nil(t, result.LastUsedAt)
+
+	err = strg.APIKey().Touch(ctx, key.ID)
+	require.NoError(t, err)
+
+	keys, err := strg.APIKey().GetAll(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.NotNil(t, keys[0].LastUsedAt)
+
+	// Only the owner can delete the key
+	err = strg.APIKey().Delete(ctx, key.ID, user.ID+1)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = strg.APIKey().Delete(ctx, key.ID, user.ID)
+	require.NoError(t, err)
+
+	deleteUser(user.ID, t)
+}
+
+func TestDeleteAllAPIKeys(t *testing.T) {
+	user := createUser(t)
+
+	for i := 0; i < 2; i++ {
+		_, prefix, hash, err := utils.CreateAPIKey()
+		require.NoError(t, err)
+
+		_, err = strg.APIKey().Create(ctx, &repo.APIKey{
+			UserID:  user.ID,
+			Name:    "channel manager",
+			Prefix:  prefix,
+			KeyHash: hash,
+			Scopes:  []string{"booking:read"},
+		})
+		require.NoError(t, err)
+	}
+
+	err := strg.APIKey().DeleteAll(ctx, user.ID)
+	require.NoError(t, err)
+
+	keys, err := strg.APIKey().GetAll(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	deleteUser(user.ID, t)
+}
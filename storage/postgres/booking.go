@@ -0,0 +1,396 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+// exclusionViolation is the postgres error code raised by bookings_no_overlap
+const exclusionViolation = "23P01"
+
+type bookingRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewBooking(db DB, timeout time.Duration) repo.BookingStorageI {
+	return &bookingRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (br *bookingRepo) Create(ctx context.Context, booking *repo.Booking) (*repo.Booking, error) {
+	ctx, cancel := withTimeout(ctx, br.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, br.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	// Lock the room row so that concurrent reservations of the same room are
+	// serialized. The exclusion constraint on bookings is the last line of defence.
+	var roomID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM rooms WHERE id = $1 FOR UPDATE`, booking.RoomID).Scan(&roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOverlap := `
+		SELECT count(1) FROM bookings
+		WHERE room_id = $1
+			AND status = ANY($2)
+			AND daterange(check_in, check_out) && daterange($3::DATE, $4::DATE)
+	`
+
+	var overlaps int64
+	err = tx.QueryRowContext(ctx,
+		queryOverlap,
+		booking.RoomID,
+		pq.Array(repo.ActiveBookingStatuses),
+		booking.CheckIn,
+		booking.CheckOut,
+	).Scan(&overlaps)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlaps > 0 {
+		return nil, repo.ErrRoomNotAvailable
+	}
+
+	queryBlocked := `
+		SELECT count(1) FROM room_blocks
+		WHERE room_id = $1
+			AND daterange(start_date, end_date) && daterange($2::DATE, $3::DATE)
+	`
+
+	var blocks int64
+	err = tx.QueryRowContext(ctx,
+		queryBlocked,
+		booking.RoomID,
+		booking.CheckIn,
+		booking.CheckOut,
+	).Scan(&blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	if blocks > 0 {
+		return nil, repo.ErrRoomNotAvailable
+	}
+
+	query := `
+		INSERT INTO bookings (
+			room_id,
+			guest_id,
+			check_in,
+			check_out,
+			guests_count,
+			total_price
+		) VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at
+	`
+
+	row := tx.QueryRowContext(ctx,
+		query,
+		booking.RoomID,
+		booking.GuestID,
+		booking.CheckIn,
+		booking.CheckOut,
+		booking.GuestsCount,
+		booking.TotalPrice,
+	)
+
+	err = row.Scan(
+		&booking.ID,
+		&booking.Status,
+		&booking.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+			return nil, repo.ErrRoomNotAvailable
+		}
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return booking, nil
+}
+
+func (br *bookingRepo) Get(ctx context.Context, id int64) (*repo.Booking, error) {
+	ctx, cancel := withTimeout(ctx, br.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			room_id,
+			guest_id,
+			check_in,
+			check_out,
+			guests_count,
+			total_price,
+			status,
+			refund_amount,
+			cancelled_at,
+			created_at,
+			updated_at
+		FROM bookings
+		WHERE id = $1
+	`
+
+	var result repo.Booking
+
+	err := br.db.GetContext(ctx, &result, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (br *bookingRepo) GetAll(ctx context.Context, params *repo.GetBookingsParams) (*repo.GetBookingsResult, error) {
+	ctx, cancel := withTimeout(ctx, br.timeout)
+	defer cancel()
+
+	result := repo.GetBookingsResult{
+		Bookings: make([]*repo.Booking, 0),
+		Count:    0,
+	}
+
+	q := newListQuery().
+		Equal("b.guest_id", params.GuestID).
+		Equal("b.room_id", params.RoomID).
+		Equal("r.property_id", params.PropertyID).
+		Equal("p.owner_id", params.OwnerID).
+		Equal("b.status", params.Status).
+		OrderBy("b.check_in", "desc").
+		Paginate(int64(params.Page), int64(params.Limit))
+
+	from := `
+		FROM bookings b
+		INNER JOIN rooms r ON r.id = b.room_id
+		INNER JOIN properties p ON p.id = r.property_id
+	`
+
+	query, args := q.Select(`
+		SELECT
+			b.id,
+			b.room_id,
+			b.guest_id,
+			b.check_in,
+			b.check_out,
+			b.guests_count,
+			b.total_price,
+			b.status,
+			b.refund_amount,
+			b.cancelled_at,
+			b.created_at,
+			b.updated_at
+		` + from)
+
+	err := br.db.SelectContext(ctx, &result.Bookings, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	queryCount, args := q.Count(`SELECT count(1) ` + from)
+
+	err = br.db.GetContext(ctx, &result.Count, queryCount, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (br *bookingRepo) GetRoomStays(ctx context.Context, params *repo.GetRoomStaysParams) ([]*repo.Booking, error) {
+	ctx, cancel := withTimeout(ctx, br.timeout)
+	defer cancel()
+
+	result := make([]*repo.Booking, 0)
+
+	query := `
+		SELECT
+			id,
+			room_id,
+			guest_id,
+			check_in,
+			check_out,
+			guests_count,
+			total_price,
+			status,
+			refund_amount,
+			cancelled_at,
+			created_at,
+			updated_at
+		FROM bookings
+		WHERE room_id = $1
+			AND check_out > $2
+			AND status = ANY($3)
+		ORDER BY check_in
+	`
+
+	err := br.db.SelectContext(ctx,
+		&result,
+		query,
+		params.RoomID,
+		params.From,
+		pq.Array(params.Statuses),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (br *bookingRepo) UpdateStatus(ctx context.Context, req *repo.UpdateBookingStatus) error {
+	ctx, cancel := withTimeout(ctx, br.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE bookings SET
+			status = $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status = ANY($3)
+	`
+
+	result, err := br.db.ExecContext(ctx,
+		query,
+		req.Status,
+		req.ID,
+		pq.Array(sourceStatuses(req.Status)),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		_, err := br.Get(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		return repo.ErrInvalidStatusTransition
+	}
+
+	return nil
+}
+
+func (br *bookingRepo) Cancel(ctx context.Context, req *repo.CancelBooking) error {
+	ctx, cancel := withTimeout(ctx, br.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, br.db)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// Lock the booking so that it cannot be claimed by a payment while it is
+	// being cancelled
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM bookings WHERE id = $1 FOR UPDATE`, req.ID).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	if !repo.CanTransitionBooking(status, repo.BookingStatusCancelled) {
+		return repo.ErrInvalidStatusTransition
+	}
+
+	var paymentStatus string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM payments WHERE booking_id = $1 AND status = ANY($2)`,
+		req.ID,
+		pq.Array(repo.ActivePaymentStatuses),
+	).Scan(&paymentStatus)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// A payment which has not been captured yet might still be, and would
+	// then not be refunded
+	if paymentStatus == repo.PaymentStatusPending || paymentStatus == repo.PaymentStatusAuthorized {
+		return repo.ErrPaymentInProgress
+	}
+
+	refundAmount := req.RefundAmount
+	if paymentStatus != repo.PaymentStatusCaptured {
+		refundAmount = 0
+	}
+
+	query := `
+		UPDATE bookings SET
+			status = $1,
+			refund_amount = $2,
+			cancelled_at = $3,
+			updated_at = $3
+		WHERE id = $4
+	`
+
+	_, err = tx.ExecContext(ctx,
+		query,
+		repo.BookingStatusCancelled,
+		refundAmount,
+		req.CancelledAt,
+		req.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sourceStatuses returns the statuses from which a booking may move to status
+func sourceStatuses(status string) []string {
+	result := make([]string, 0)
+
+	for from := range repo.BookingTransitions {
+		if repo.CanTransitionBooking(from, status) {
+			result = append(result, from)
+		}
+	}
+
+	return result
+}
+
+// roomFreeFilter returns a condition which holds when neither an active booking
+// nor a block of the room aliased r overlaps the stay given by the checkIn and
+// checkOut placeholders
+func roomFreeFilter(statuses, checkIn, checkOut int) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM bookings b
+		WHERE b.room_id = r.id
+			AND b.status = ANY($%d)
+			AND daterange(b.check_in, b.check_out) && daterange($%d::DATE, $%d::DATE)
+	) AND NOT %s`, statuses, checkIn, checkOut, roomBlockedFilter(checkIn, checkOut))
+}
@@ -0,0 +1,264 @@
+package postgres_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func stayDates(fromNow, nights int) (time.Time, time.Time) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	checkIn := today.AddDate(0, 0, fromNow)
+	return checkIn, checkIn.AddDate(0, 0, nights)
+}
+
+func createBooking(t *testing.T) *repo.Booking {
+	room := createRoom(t)
+	guest := createUser(t)
+	checkIn, checkOut := stayDates(1, 3)
+
+	booking, err := strg.Booking().Create(ctx, &repo.Booking{
+		RoomID:      room.ID,
+		GuestID:     guest.ID,
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		GuestsCount: 1,
+		TotalPrice:  300,
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, booking)
+	require.Equal(t, repo.BookingStatusPending, booking.Status)
+
+	return booking
+}
+
+func TestCreateBooking(t *testing.T) {
+	b := createBooking(t)
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCreateBookingOverlap(t *testing.T) {
+	b := createBooking(t)
+
+	// Starts on the last night of b
+	checkIn := b.CheckOut.AddDate(0, 0, -1)
+
+	_, err := strg.Booking().Create(ctx, &repo.Booking{
+		RoomID:      b.RoomID,
+		GuestID:     b.GuestID,
+		CheckIn:     checkIn,
+		CheckOut:    checkIn.AddDate(0, 0, 2),
+		GuestsCount: 1,
+		TotalPrice:  200,
+	})
+	require.ErrorIs(t, err, repo.ErrRoomNotAvailable)
+
+	// Back-to-back stays do not overlap
+	_, err = strg.Booking().Create(ctx, &repo.Booking{
+		RoomID:      b.RoomID,
+		GuestID:     b.GuestID,
+		CheckIn:     b.CheckOut,
+		CheckOut:    b.CheckOut.AddDate(0, 0, 1),
+		GuestsCount: 1,
+		TotalPrice:  100,
+	})
+	require.NoError(t, err)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCreateBookingAfterCancel(t *testing.T) {
+	b := createBooking(t)
+
+	err := strg.Booking().UpdateStatus(ctx, &repo.UpdateBookingStatus{
+		ID:     b.ID,
+		Status: repo.BookingStatusCancelled,
+	})
+	require.NoError(t, err)
+
+	_, err = strg.Booking().Create(ctx, &repo.Booking{
+		RoomID:      b.RoomID,
+		GuestID:     b.GuestID,
+		CheckIn:     b.CheckIn,
+		CheckOut:    b.CheckOut,
+		GuestsCount: 1,
+		TotalPrice:  300,
+	})
+	require.NoError(t, err)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCreateBookingConcurrent(t *testing.T) {
+	room := createRoom(t)
+	guest := createUser(t)
+	checkIn, checkOut := stayDates(10, 2)
+
+	const workers = 10
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded int
+		rejected  int
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+
+			// Every request overlaps with every other one by at least a night
+			_, err := strg.Booking().Create(ctx, &repo.Booking{
+				RoomID:      room.ID,
+				GuestID:     guest.ID,
+				CheckIn:     checkIn.AddDate(0, 0, offset%2),
+				CheckOut:    checkOut.AddDate(0, 0, offset%2),
+				GuestsCount: 1,
+				TotalPrice:  200,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, repo.ErrRoomNotAvailable):
+				rejected++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	require.Equal(t, 1, succeeded)
+	require.Equal(t, workers-1, rejected)
+
+	bookings, err := strg.Booking().GetAll(ctx, &repo.GetBookingsParams{
+		Limit:  10,
+		Page:   1,
+		RoomID: room.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, int(bookings.Count))
+
+	deleteRoom(room.ID, t)
+}
+
+func TestGetBooking(t *testing.T) {
+	b := createBooking(t)
+
+	booking, err := strg.Booking().Get(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, b.CheckIn.Format("2006-01-02"), booking.CheckIn.Format("2006-01-02"))
+	require.Equal(t, b.CheckOut.Format("2006-01-02"), booking.CheckOut.Format("2006-01-02"))
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestGetAllBookings(t *testing.T) {
+	b := createBooking(t)
+
+	bookings, err := strg.Booking().GetAll(ctx, &repo.GetBookingsParams{
+		Limit:   10,
+		Page:    1,
+		GuestID: b.GuestID,
+		Status:  repo.BookingStatusPending,
+	})
+
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(bookings.Bookings), 1)
+	require.GreaterOrEqual(t, int(bookings.Count), 1)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestUpdateBookingStatus(t *testing.T) {
+	b := createBooking(t)
+
+	for _, status := range []string{
+		repo.BookingStatusConfirmed,
+		repo.BookingStatusCheckedIn,
+		repo.BookingStatusCompleted,
+	} {
+		err := strg.Booking().UpdateStatus(ctx, &repo.UpdateBookingStatus{
+			ID:     b.ID,
+			Status: status,
+		})
+		require.NoError(t, err)
+	}
+
+	err := strg.Booking().UpdateStatus(ctx, &repo.UpdateBookingStatus{
+		ID:     b.ID,
+		Status: repo.BookingStatusCancelled,
+	})
+	require.ErrorIs(t, err, repo.ErrInvalidStatusTransition)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCancelBooking(t *testing.T) {
+	b := createBooking(t)
+
+	cancelledAt := time.Now()
+
+	err := strg.Booking().Cancel(ctx, &repo.CancelBooking{
+		ID:           b.ID,
+		RefundAmount: 12.5,
+		CancelledAt:  cancelledAt,
+	})
+	require.NoError(t, err)
+
+	booking, err := strg.Booking().Get(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.BookingStatusCancelled, booking.Status)
+	require.NotNil(t, booking.RefundAmount)
+	require.Equal(t, 0.0, *booking.RefundAmount)
+	require.NotNil(t, booking.CancelledAt)
+	require.WithinDuration(t, cancelledAt, *booking.CancelledAt, time.Second)
+
+	err = strg.Booking().Cancel(ctx, &repo.CancelBooking{
+		ID:          b.ID,
+		CancelledAt: time.Now(),
+	})
+	require.ErrorIs(t, err, repo.ErrInvalidStatusTransition)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCancelPaidBooking(t *testing.T) {
+	b := createBooking(t)
+	p := createPayment(t, b)
+
+	_, err := strg.Payment().ApplyEvent(ctx, &repo.PaymentEvent{
+		ID:                faker.UUIDHyphenated(),
+		Type:              repo.PaymentEventCaptured,
+		ProviderPaymentID: p.ProviderPaymentID,
+		Amount:            p.Amount,
+	})
+	require.NoError(t, err)
+
+	err = strg.Booking().Cancel(ctx, &repo.CancelBooking{
+		ID:           b.ID,
+		RefundAmount: 12.5,
+		CancelledAt:  time.Now(),
+	})
+	require.NoError(t, err)
+
+	booking, err := strg.Booking().Get(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.BookingStatusCancelled, booking.Status)
+	require.NotNil(t, booking.RefundAmount)
+	require.Equal(t, 12.5, *booking.RefundAmount)
+
+	deleteRoom(b.RoomID, t)
+}
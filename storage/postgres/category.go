@@ -1,24 +1,29 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"time"
 
 	"github.com/ibrat-muslim/booking-service/storage/repo"
-	"github.com/jmoiron/sqlx"
 )
 
 type categoryRepo struct {
-	db *sqlx.DB
+	db      DB
+	timeout time.Duration
 }
 
-func NewCategory(db *sqlx.DB) repo.CategoryStorageI {
+func NewCategory(db DB, timeout time.Duration) repo.CategoryStorageI {
 	return &categoryRepo{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
-func (cr *categoryRepo) Create(category *repo.Category) (*repo.Category, error) {
+func (cr *categoryRepo) Create(ctx context.Context, category *repo.Category) (*repo.Category, error) {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	query := `
 		INSERT INTO categories (
 			title
@@ -26,7 +31,7 @@ func (cr *categoryRepo) Create(category *repo.Category) (*repo.Category, error)
 		RETURNING id, created_at
 	`
 
-	row := cr.db.QueryRow(
+	row := cr.db.QueryRowContext(ctx,
 		query,
 		category.Title,
 	)
@@ -43,7 +48,10 @@ func (cr *categoryRepo) Create(category *repo.Category) (*repo.Category, error)
 	return category, nil
 }
 
-func (cr *categoryRepo) Get(id int64) (*repo.Category, error) {
+func (cr *categoryRepo) Get(ctx context.Context, id int64) (*repo.Category, error) {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	query := `
 		SELECT
 			id,
@@ -55,7 +63,7 @@ func (cr *categoryRepo) Get(id int64) (*repo.Category, error) {
 
 	var result repo.Category
 
-	err := cr.db.Get(&result, query, id)
+	err := cr.db.GetContext(ctx, &result, query, id)
 
 	if err != nil {
 		return nil, err
@@ -64,44 +72,37 @@ func (cr *categoryRepo) Get(id int64) (*repo.Category, error) {
 	return &result, nil
 }
 
-func (cr *categoryRepo) GetAll(params *repo.GetCategoriesParams) (*repo.GetCategoriesResult, error) {
+func (cr *categoryRepo) GetAll(ctx context.Context, params *repo.GetCategoriesParams) (*repo.GetCategoriesResult, error) {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	result := repo.GetCategoriesResult{
 		Categories: make([]*repo.Category, 0),
 		Count:      0,
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
-
-	filter := ""
+	q := newListQuery().
+		Search(params.Search, "title").
+		OrderBy("created_at", "desc").
+		Paginate(int64(params.Page), int64(params.Limit))
 
-	if params.Search != "" {
-		str := "%" + params.Search + "%"
-		filter += fmt.Sprintf(`
-				WHERE title ILIKE '%s'`, str,
-		)
-	}
-
-	query := `
+	query, args := q.Select(`
 		SELECT
 			id,
 			title,
 			created_at
 		FROM categories
-		` + filter + `
-		ORDER BY created_at DESC
-		` + limit
+	`)
 
-	err := cr.db.Select(&result.Categories, query)
+	err := cr.db.SelectContext(ctx, &result.Categories, query, args...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	queryCount := `SELECT count(1) FROM categories ` + filter
+	queryCount, args := q.Count(`SELECT count(1) FROM categories `)
 
-	err = cr.db.Get(&result.Count, queryCount)
+	err = cr.db.GetContext(ctx, &result.Count, queryCount, args...)
 
 	if err != nil {
 		return nil, err
@@ -110,14 +111,17 @@ func (cr *categoryRepo) GetAll(params *repo.GetCategoriesParams) (*repo.GetCateg
 	return &result, nil
 }
 
-func (cr *categoryRepo) Update(category *repo.Category) error {
+func (cr *categoryRepo) Update(ctx context.Context, category *repo.Category) error {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	query := `
 		UPDATE categories SET
 			title = $1
 		WHERE id = $2
 	`
 
-	result, err := cr.db.Exec(
+	result, err := cr.db.ExecContext(ctx,
 		query,
 		category.Title,
 		category.ID,
@@ -140,10 +144,13 @@ func (cr *categoryRepo) Update(category *repo.Category) error {
 	return nil
 }
 
-func (cr *categoryRepo) Delete(id int64) error {
+func (cr *categoryRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, cr.timeout)
+	defer cancel()
+
 	query := `DELETE FROM posts WHERE category_id = $1`
 
-	_, err := cr.db.Exec(query, id)
+	_, err := cr.db.ExecContext(ctx, query, id)
 
 	if err != nil {
 		return err
@@ -151,7 +158,7 @@ func (cr *categoryRepo) Delete(id int64) error {
 
 	query = `DELETE FROM categories WHERE id = $1`
 
-	resutl, err := cr.db.Exec(query, id)
+	resutl, err := cr.db.ExecContext(ctx, query, id)
 
 	if err != nil {
 		return err
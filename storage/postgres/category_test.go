@@ -9,7 +9,7 @@ import (
 )
 
 func createCategory(t *testing.T) *repo.Category {
-	category, err := strg.Category().Create(&repo.Category{
+	category, err := strg.Category().Create(ctx, &repo.Category{
 		Title: faker.Sentence(),
 	})
 
@@ -20,7 +20,7 @@ func createCategory(t *testing.T) *repo.Category {
 }
 
 func deleteCategory(id int64, t *testing.T) {
-	err := strg.Category().Delete(id)
+	err := strg.Category().Delete(ctx, id)
 	require.NoError(t, err)
 }
 
@@ -32,7 +32,7 @@ func TestCreateCategory(t *testing.T) {
 func TestGetCategory(t *testing.T) {
 	c := createCategory(t)
 
-	category, err := strg.Category().Get(c.ID)
+	category, err := strg.Category().Get(ctx, c.ID)
 	require.NoError(t, err)
 	require.NotEmpty(t, category)
 
@@ -42,7 +42,7 @@ func TestGetCategory(t *testing.T) {
 func TestGetAllCategories(t *testing.T) {
 	c := createCategory(t)
 
-	categories, err := strg.Category().GetAll(&repo.GetCategoriesParams{
+	categories, err := strg.Category().GetAll(ctx, &repo.GetCategoriesParams{
 		Limit:  10,
 		Page:   1,
 		Search: c.Title,
@@ -55,12 +55,31 @@ func TestGetAllCategories(t *testing.T) {
 	deleteCategory(c.ID, t)
 }
 
+func TestGetAllCategoriesHostileSearch(t *testing.T) {
+	c := createCategory(t)
+
+	categories, err := strg.Category().GetAll(ctx, &repo.GetCategoriesParams{
+		Limit:  10,
+		Page:   1,
+		Search: `'; DROP TABLE categories; --`,
+	})
+
+	require.NoError(t, err)
+	require.Empty(t, categories.Categories)
+
+	category, err := strg.Category().Get(ctx, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, c.ID, category.ID)
+
+	deleteCategory(c.ID, t)
+}
+
 func TestUpdateCategory(t *testing.T) {
 	c := createCategory(t)
 
 	c.Title = faker.Sentence()
 
-	err := strg.Category().Update(c)
+	err := strg.Category().Update(ctx, c)
 	require.NoError(t, err)
 
 	deleteCategory(c.ID, t)
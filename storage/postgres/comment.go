@@ -1,24 +1,29 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"time"
 
 	"github.com/ibrat-muslim/booking-service/storage/repo"
-	"github.com/jmoiron/sqlx"
 )
 
 type commentRepo struct {
-	db *sqlx.DB
+	db      DB
+	timeout time.Duration
 }
 
-func NewComment(db *sqlx.DB) repo.CommentStorageI {
+func NewComment(db DB, timeout time.Duration) repo.CommentStorageI {
 	return &commentRepo{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
-func (cmr *commentRepo) Create(comment *repo.Comment) (*repo.Comment, error) {
+func (cmr *commentRepo) Create(ctx context.Context, comment *repo.Comment) (*repo.Comment, error) {
+	ctx, cancel := withTimeout(ctx, cmr.timeout)
+	defer cancel()
+
 	query := `
 		INSERT INTO comments (
 			post_id,
@@ -28,7 +33,7 @@ func (cmr *commentRepo) Create(comment *repo.Comment) (*repo.Comment, error) {
 		RETURNING id, created_at
 	`
 
-	row := cmr.db.QueryRow(
+	row := cmr.db.QueryRowContext(ctx,
 		query,
 		comment.PostID,
 		comment.UserID,
@@ -47,27 +52,22 @@ func (cmr *commentRepo) Create(comment *repo.Comment) (*repo.Comment, error) {
 	return comment, nil
 }
 
-func (cmr *commentRepo) GetAll(params *repo.GetCommentsParams) (*repo.GetCommentsResult, error) {
+func (cmr *commentRepo) GetAll(ctx context.Context, params *repo.GetCommentsParams) (*repo.GetCommentsResult, error) {
+	ctx, cancel := withTimeout(ctx, cmr.timeout)
+	defer cancel()
+
 	result := repo.GetCommentsResult{
 		Comments: make([]*repo.Comment, 0),
 		Count:    0,
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
-
-	filter := " WHERE true "
-
-	if params.PostID != 0 {
-		filter += fmt.Sprintf(" AND c.post_id = %d ", params.PostID)
-	}
-
-	if params.UserID != 0 {
-		filter += fmt.Sprintf(" AND c.user_id = %d ", params.UserID)
-	}
+	q := newListQuery().
+		Equal("c.post_id", params.PostID).
+		Equal("c.user_id", params.UserID).
+		OrderBy("c.created_at", "desc").
+		Paginate(int64(params.Page), int64(params.Limit))
 
-	query := `
+	query, args := q.Select(`
 		SELECT
 			c.id,
 			c.post_id,
@@ -81,11 +81,9 @@ func (cmr *commentRepo) GetAll(params *repo.GetCommentsParams) (*repo.GetComment
 			u.profile_image_url
 		FROM comments c
 		INNER JOIN users u ON u.id = c.user_id
-		` + filter + `
-		ORDER BY c.created_at DESC
-		` + limit
+	`)
 
-	rows, err := cmr.db.Query(query)
+	rows, err := cmr.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -114,11 +112,11 @@ func (cmr *commentRepo) GetAll(params *repo.GetCommentsParams) (*repo.GetComment
 		result.Comments = append(result.Comments, &comment)
 	}
 
-	queryCount := `
+	queryCount, args := q.Count(`
 		SELECT count(1) FROM comments c
-		INNER JOIN users u ON u.id = c.user_id ` + filter
+		INNER JOIN users u ON u.id = c.user_id `)
 
-	err = cmr.db.QueryRow(queryCount).Scan(&result.Count)
+	err = cmr.db.QueryRowContext(ctx, queryCount, args...).Scan(&result.Count)
 	if err != nil {
 		return nil, err
 	}
@@ -126,7 +124,27 @@ func (cmr *commentRepo) GetAll(params *repo.GetCommentsParams) (*repo.GetComment
 	return &result, nil
 }
 
-func (cmr *commentRepo) Update(comment *repo.Comment) error {
+func (cmr *commentRepo) GetAuthorID(ctx context.Context, id int64) (int64, error) {
+	ctx, cancel := withTimeout(ctx, cmr.timeout)
+	defer cancel()
+
+	query := `SELECT user_id FROM comments WHERE id = $1`
+
+	var userID int64
+
+	err := cmr.db.GetContext(ctx, &userID, query, id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (cmr *commentRepo) Update(ctx context.Context, comment *repo.Comment) error {
+	ctx, cancel := withTimeout(ctx, cmr.timeout)
+	defer cancel()
+
 	query := `
 		UPDATE comments SET
 			description = $1,
@@ -134,7 +152,7 @@ func (cmr *commentRepo) Update(comment *repo.Comment) error {
 		WHERE id = $3
 	`
 
-	result, err := cmr.db.Exec(
+	result, err := cmr.db.ExecContext(ctx,
 		query,
 		comment.Description,
 		comment.UpdatedAt,
@@ -158,10 +176,13 @@ func (cmr *commentRepo) Update(comment *repo.Comment) error {
 	return nil
 }
 
-func (cmr *commentRepo) Delete(id int64) error {
+func (cmr *commentRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, cmr.timeout)
+	defer cancel()
+
 	query := `DELETE FROM comments WHERE id = $1`
 
-	result, err := cmr.db.Exec(query, id)
+	result, err := cmr.db.ExecContext(ctx, query, id)
 
 	if err != nil {
 		return err
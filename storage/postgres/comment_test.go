@@ -12,7 +12,7 @@ func createComment(t *testing.T) *repo.Comment {
 	post := createPost(t)
 	user := createUser(t)
 
-	comment, err := strg.Comment().Create(&repo.Comment{
+	comment, err := strg.Comment().Create(ctx, &repo.Comment{
 		PostID:      post.ID,
 		UserID:      user.ID,
 		Description: faker.Sentence(),
@@ -25,7 +25,7 @@ func createComment(t *testing.T) *repo.Comment {
 }
 
 func deleteComment(id int64, t *testing.T) {
-	err := strg.Comment().Delete(id)
+	err := strg.Comment().Delete(ctx, id)
 	require.NoError(t, err)
 }
 
@@ -37,7 +37,7 @@ func TestCreateComment(t *testing.T) {
 func TestGetAllComments(t *testing.T) {
 	cm := createComment(t)
 
-	comments, err := strg.Comment().GetAll(&repo.GetCommentsParams{
+	comments, err := strg.Comment().GetAll(ctx, &repo.GetCommentsParams{
 		Limit: 10,
 		Page:  1,
 	})
@@ -49,12 +49,22 @@ func TestGetAllComments(t *testing.T) {
 	deleteComment(cm.ID, t)
 }
 
+func TestGetCommentAuthorID(t *testing.T) {
+	cm := createComment(t)
+
+	userID, err := strg.Comment().GetAuthorID(ctx, cm.ID)
+	require.NoError(t, err)
+	require.Equal(t, cm.UserID, userID)
+
+	deleteComment(cm.ID, t)
+}
+
 func TestUpdateComment(t *testing.T) {
 	cm := createComment(t)
 
 	cm.Description = faker.Sentence()
 
-	err := strg.Comment().Update(cm)
+	err := strg.Comment().Update(ctx, cm)
 	require.NoError(t, err)
 
 	deleteComment(cm.ID, t)
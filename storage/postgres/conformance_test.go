@@ -0,0 +1,11 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, strg)
+}
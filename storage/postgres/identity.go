@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type identityRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewIdentity(db DB, timeout time.Duration) repo.IdentityStorageI {
+	return &identityRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (ir *identityRepo) Create(ctx context.Context, identity *repo.Identity) (*repo.Identity, error) {
+	ctx, cancel := withTimeout(ctx, ir.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO identities (
+			provider,
+			subject,
+			user_id,
+			email
+		) VALUES($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	row := ir.db.QueryRowContext(ctx,
+		query,
+		identity.Provider,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+	)
+
+	err := row.Scan(
+		&identity.ID,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
+
+func (ir *identityRepo) Get(ctx context.Context, provider, subject string) (*repo.Identity, error) {
+	ctx, cancel := withTimeout(ctx, ir.timeout)
+	defer cancel()
+
+	var result repo.Identity
+
+	query := `
+		SELECT
+			id,
+			provider,
+			subject,
+			user_id,
+			email,
+			created_at
+		FROM identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	err := ir.db.GetContext(ctx, &result, query, provider, subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,32 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentity(t *testing.T) {
+	user := createUser(t)
+
+	identity, err := strg.Identity().Create(ctx, &repo.Identity{
+		Provider: "google",
+		Subject:  uuid.NewString(),
+		UserID:   user.ID,
+		Email:    user.Email,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, identity)
+
+	result, err := strg.Identity().Get(ctx, "google", identity.Subject)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, result.UserID)
+
+	_, err = strg.Identity().Get(ctx, "github", identity.Subject)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	deleteUser(user.ID, t)
+}
@@ -1,25 +1,35 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/ibrat-muslim/booking-service/storage/repo"
-	"github.com/jmoiron/sqlx"
 )
 
 type likeRepo struct {
-	db *sqlx.DB
+	db      DB
+	timeout time.Duration
 }
 
-func NewLike(db *sqlx.DB) repo.LikeStorageI {
+func NewLike(db DB, timeout time.Duration) repo.LikeStorageI {
 	return &likeRepo{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
-func (lr *likeRepo) CreateOrUpdate(like *repo.Like) error {
-	l, err := lr.Get(like.PostID, like.UserID)
+// CreateOrUpdate toggles the like of the user: a new like is created, the
+// same like again removes it and the opposite one replaces it. The like is
+// read before it is written, so concurrent toggles of the user must be run
+// in one transaction each, as storage.ToggleLike does.
+func (lr *likeRepo) CreateOrUpdate(ctx context.Context, like *repo.Like) error {
+	ctx, cancel := withTimeout(ctx, lr.timeout)
+	defer cancel()
+
+	l, err := getLike(ctx, lr.db, like.PostID, like.UserID)
 	if errors.Is(err, sql.ErrNoRows) {
 		query := `
 			INSERT INTO likes (
@@ -27,46 +37,38 @@ func (lr *likeRepo) CreateOrUpdate(like *repo.Like) error {
 				user_id,
 				status
 			) VALUES($1, $2, $3)
-			RETURNING id
-			`
+		`
 
-		_, err := lr.db.Exec(
+		_, err := lr.db.ExecContext(ctx,
 			query,
 			like.PostID,
 			like.UserID,
 			like.Status,
 		)
-		if err != nil {
-			return err
-		}
-
-	} else if l != nil && err == nil {
-		if l.Status == like.Status {
-			query := `DELETE FROM likes WHERE id = $1`
-
-			_, err := lr.db.Exec(query, l.ID)
-			if err != nil {
-				return err
-			}
-
-		} else {
-			query := `UPDATE likes SET status = $1 WHERE id = $2`
-
-			_, err := lr.db.Exec(query, like.Status, l.ID)
-			if err != nil {
-				return err
-			}
-		}
+		return err
 	}
 
-	if !errors.Is(err, sql.ErrNoRows) {
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if l.Status == like.Status {
+		_, err := lr.db.ExecContext(ctx, `DELETE FROM likes WHERE id = $1`, l.ID)
+		return err
+	}
+
+	_, err = lr.db.ExecContext(ctx, `UPDATE likes SET status = $1 WHERE id = $2`, like.Status, l.ID)
+	return err
 }
 
-func (l *likeRepo) Get(postID, userID int64) (*repo.Like, error) {
+func (l *likeRepo) Get(ctx context.Context, postID, userID int64) (*repo.Like, error) {
+	ctx, cancel := withTimeout(ctx, l.timeout)
+	defer cancel()
+
+	return getLike(ctx, l.db, postID, userID)
+}
+
+func getLike(ctx context.Context, db DB, postID, userID int64) (*repo.Like, error) {
 	query := `
 		SELECT
 			id,
@@ -79,7 +81,7 @@ func (l *likeRepo) Get(postID, userID int64) (*repo.Like, error) {
 
 	var result repo.Like
 
-	err := l.db.Get(&result, query, postID, userID)
+	err := db.GetContext(ctx, &result, query, postID, userID)
 
 	if err != nil {
 		return nil, err
@@ -88,7 +90,10 @@ func (l *likeRepo) Get(postID, userID int64) (*repo.Like, error) {
 	return &result, nil
 }
 
-func (l *likeRepo) GetLikesDislikesCount(postID int64) (*repo.LikesDislikesCountsResult, error) {
+func (l *likeRepo) GetLikesDislikesCount(ctx context.Context, postID int64) (*repo.LikesDislikesCountsResult, error) {
+	ctx, cancel := withTimeout(ctx, l.timeout)
+	defer cancel()
+
 	var result repo.LikesDislikesCountsResult
 
 	query := `
@@ -99,7 +104,7 @@ func (l *likeRepo) GetLikesDislikesCount(postID int64) (*repo.LikesDislikesCount
 		WHERE post_id = $1
 		`
 
-	err := l.db.Get(&result, query, postID)
+	err := l.db.GetContext(ctx, &result, query, postID)
 
 	if err != nil {
 		return nil, err
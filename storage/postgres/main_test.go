@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 var (
 	strg storage.StorageI
+	ctx  = context.Background()
 )
 
 func TestMain(m *testing.M) {
@@ -33,6 +35,6 @@ func TestMain(m *testing.M) {
 		log.Fatalf("failed to open connection: %v", err)
 	}
 
-	strg = storage.NewStoragePg(db)
+	strg = storage.NewStoragePg(db, cfg.Postgres.QueryTimeout)
 	os.Exit(m.Run())
 }
@@ -0,0 +1,298 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type paymentRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewPayment(db DB, timeout time.Duration) repo.PaymentStorageI {
+	return &paymentRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (pr *paymentRepo) Create(ctx context.Context, payment *repo.Payment) (*repo.Payment, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, pr.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	// Lock the booking so that it cannot be cancelled or paid twice while
+	// the payment is being claimed
+	var status string
+	err = tx.QueryRowContext(ctx,
+		`SELECT status FROM bookings WHERE id = $1 FOR UPDATE`,
+		payment.BookingID,
+	).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != repo.BookingStatusPending {
+		return nil, repo.ErrBookingNotPending
+	}
+
+	query := `
+		INSERT INTO payments (
+			booking_id,
+			provider_payment_id,
+			amount,
+			status
+		) VALUES($1, NULLIF($2, ''), $3, $4)
+		RETURNING id, refunded_amount, created_at
+	`
+
+	row := tx.QueryRowContext(ctx,
+		query,
+		payment.BookingID,
+		payment.ProviderPaymentID,
+		payment.Amount,
+		payment.Status,
+	)
+
+	err = row.Scan(
+		&payment.ID,
+		&payment.RefundedAmount,
+		&payment.CreatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation && pqErr.Constraint == "payments_booking_id_active_idx" {
+			return nil, repo.ErrPaymentInProgress
+		}
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (pr *paymentRepo) Authorize(ctx context.Context, id int64, providerPaymentID string) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE payments SET
+			provider_payment_id = $1,
+			status = $2,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status = $4
+	`
+
+	return execAffectingRow(ctx, pr.db,
+		query,
+		providerPaymentID,
+		repo.PaymentStatusAuthorized,
+		id,
+		repo.PaymentStatusPending,
+	)
+}
+
+func (pr *paymentRepo) Fail(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE payments SET
+			status = $1,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status IN($3, $4)
+	`
+
+	return execAffectingRow(ctx, pr.db,
+		query,
+		repo.PaymentStatusFailed,
+		id,
+		repo.PaymentStatusPending,
+		repo.PaymentStatusAuthorized,
+	)
+}
+
+func (pr *paymentRepo) Get(ctx context.Context, id int64) (*repo.Payment, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			booking_id,
+			COALESCE(provider_payment_id, '') AS provider_payment_id,
+			amount,
+			refunded_amount,
+			status,
+			created_at,
+			updated_at
+		FROM payments
+		WHERE id = $1
+	`
+
+	var result repo.Payment
+
+	err := pr.db.GetContext(ctx, &result, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (pr *paymentRepo) GetByBooking(ctx context.Context, bookingID int64) (*repo.Payment, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			booking_id,
+			COALESCE(provider_payment_id, '') AS provider_payment_id,
+			amount,
+			refunded_amount,
+			status,
+			created_at,
+			updated_at
+		FROM payments
+		WHERE booking_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var result repo.Payment
+
+	err := pr.db.GetContext(ctx, &result, query, bookingID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (pr *paymentRepo) ApplyEvent(ctx context.Context, event *repo.PaymentEvent) (bool, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, pr.db)
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	// Lock the payment so that concurrent deliveries of its events are applied one by one
+	var bookingID int64
+	err = tx.QueryRowContext(ctx,
+		`SELECT booking_id FROM payments WHERE provider_payment_id = $1 FOR UPDATE`,
+		event.ProviderPaymentID,
+	).Scan(&bookingID)
+	if err != nil {
+		return false, err
+	}
+
+	queryEvent := `
+		INSERT INTO payment_events (
+			id,
+			type,
+			provider_payment_id,
+			amount
+		) VALUES($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	result, err := tx.ExecContext(ctx,
+		queryEvent,
+		event.ID,
+		event.Type,
+		event.ProviderPaymentID,
+		event.Amount,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rowsCount, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if rowsCount == 0 {
+		return false, nil
+	}
+
+	switch event.Type {
+	case repo.PaymentEventCaptured:
+		result, err = tx.ExecContext(ctx, `
+			UPDATE payments SET
+				status = $1,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE provider_payment_id = $2 AND status = $3
+		`, repo.PaymentStatusCaptured, event.ProviderPaymentID, repo.PaymentStatusAuthorized)
+		if err != nil {
+			return false, err
+		}
+
+		rowsCount, err = result.RowsAffected()
+		if err != nil {
+			return false, err
+		}
+
+		if rowsCount > 0 {
+			_, err = tx.ExecContext(ctx, `
+				UPDATE bookings SET
+					status = $1,
+					updated_at = CURRENT_TIMESTAMP
+				WHERE id = $2 AND status = $3
+			`, repo.BookingStatusConfirmed, bookingID, repo.BookingStatusPending)
+			if err != nil {
+				return false, err
+			}
+		}
+	case repo.PaymentEventRefunded:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE payments SET
+				status = $1,
+				refunded_amount = GREATEST(refunded_amount, $2),
+				updated_at = CURRENT_TIMESTAMP
+			WHERE provider_payment_id = $3 AND status IN($4, $1)
+		`, repo.PaymentStatusRefunded, event.Amount, event.ProviderPaymentID, repo.PaymentStatusCaptured)
+		if err != nil {
+			return false, err
+		}
+	case repo.PaymentEventFailed:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE payments SET
+				status = $1,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE provider_payment_id = $2 AND status = $3
+		`, repo.PaymentStatusFailed, event.ProviderPaymentID, repo.PaymentStatusAuthorized)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
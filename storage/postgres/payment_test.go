@@ -0,0 +1,224 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func createPayment(t *testing.T, booking *repo.Booking) *repo.Payment {
+	payment, err := strg.Payment().Create(ctx, &repo.Payment{
+		BookingID:         booking.ID,
+		ProviderPaymentID: faker.UUIDHyphenated(),
+		Amount:            booking.TotalPrice,
+		Status:            repo.PaymentStatusAuthorized,
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, payment)
+
+	return payment
+}
+
+func TestCreatePayment(t *testing.T) {
+	b := createBooking(t)
+	p := createPayment(t, b)
+
+	payment, err := strg.Payment().GetByBooking(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.ID, payment.ID)
+	require.Equal(t, repo.PaymentStatusAuthorized, payment.Status)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestApplyPaymentEvent(t *testing.T) {
+	b := createBooking(t)
+	p := createPayment(t, b)
+
+	captured := repo.PaymentEvent{
+		ID:                faker.UUIDHyphenated(),
+		Type:              repo.PaymentEventCaptured,
+		ProviderPaymentID: p.ProviderPaymentID,
+		Amount:            p.Amount,
+	}
+
+	applied, err := strg.Payment().ApplyEvent(ctx, &captured)
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	applied, err = strg.Payment().ApplyEvent(ctx, &captured)
+	require.NoError(t, err)
+	require.False(t, applied)
+
+	payment, err := strg.Payment().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.PaymentStatusCaptured, payment.Status)
+
+	booking, err := strg.Booking().Get(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.BookingStatusConfirmed, booking.Status)
+
+	applied, err = strg.Payment().ApplyEvent(ctx, &repo.PaymentEvent{
+		ID:                faker.UUIDHyphenated(),
+		Type:              repo.PaymentEventRefunded,
+		ProviderPaymentID: p.ProviderPaymentID,
+		Amount:            100,
+	})
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	payment, err = strg.Payment().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.PaymentStatusRefunded, payment.Status)
+	require.Equal(t, 100.0, payment.RefundedAmount)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestApplyPaymentEventFailed(t *testing.T) {
+	b := createBooking(t)
+	p := createPayment(t, b)
+
+	applied, err := strg.Payment().ApplyEvent(ctx, &repo.PaymentEvent{
+		ID:                faker.UUIDHyphenated(),
+		Type:              repo.PaymentEventFailed,
+		ProviderPaymentID: p.ProviderPaymentID,
+	})
+	require.NoError(t, err)
+	require.True(t, applied)
+
+	payment, err := strg.Payment().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.PaymentStatusFailed, payment.Status)
+
+	booking, err := strg.Booking().Get(ctx, b.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.BookingStatusPending, booking.Status)
+
+	_, err = strg.Payment().ApplyEvent(ctx, &repo.PaymentEvent{
+		ID:                faker.UUIDHyphenated(),
+		Type:              repo.PaymentEventCaptured,
+		ProviderPaymentID: faker.UUIDHyphenated(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestAuthorizePayment(t *testing.T) {
+	b := createBooking(t)
+
+	p, err := strg.Payment().Create(ctx, &repo.Payment{
+		BookingID: b.ID,
+		Amount:    b.TotalPrice,
+		Status:    repo.PaymentStatusPending,
+	})
+	require.NoError(t, err)
+
+	payment, err := strg.Payment().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.PaymentStatusPending, payment.Status)
+	require.Empty(t, payment.ProviderPaymentID)
+
+	providerPaymentID := faker.UUIDHyphenated()
+
+	err = strg.Payment().Authorize(ctx, p.ID, providerPaymentID)
+	require.NoError(t, err)
+
+	payment, err = strg.Payment().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.PaymentStatusAuthorized, payment.Status)
+	require.Equal(t, providerPaymentID, payment.ProviderPaymentID)
+
+	err = strg.Payment().Authorize(ctx, p.ID, faker.UUIDHyphenated())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCreatePaymentConcurrent(t *testing.T) {
+	b := createBooking(t)
+
+	const workers = 5
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		succeeded  int
+		inProgress int
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := strg.Payment().Create(ctx, &repo.Payment{
+				BookingID: b.ID,
+				Amount:    b.TotalPrice,
+				Status:    repo.PaymentStatusPending,
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case err == nil:
+				succeeded++
+			case errors.Is(err, repo.ErrPaymentInProgress):
+				inProgress++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	require.Equal(t, 1, succeeded)
+	require.Equal(t, workers-1, inProgress)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestCancelBookingWithPaymentInProgress(t *testing.T) {
+	b := createBooking(t)
+
+	p, err := strg.Payment().Create(ctx, &repo.Payment{
+		BookingID: b.ID,
+		Amount:    b.TotalPrice,
+		Status:    repo.PaymentStatusPending,
+	})
+	require.NoError(t, err)
+
+	err = strg.Booking().Cancel(ctx, &repo.CancelBooking{
+		ID:          b.ID,
+		CancelledAt: time.Now(),
+	})
+	require.ErrorIs(t, err, repo.ErrPaymentInProgress)
+
+	err = strg.Payment().Fail(ctx, p.ID)
+	require.NoError(t, err)
+
+	err = strg.Booking().Cancel(ctx, &repo.CancelBooking{
+		ID:          b.ID,
+		CancelledAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	_, err = strg.Payment().Create(ctx, &repo.Payment{
+		BookingID: b.ID,
+		Amount:    b.TotalPrice,
+		Status:    repo.PaymentStatusPending,
+	})
+	require.ErrorIs(t, err, repo.ErrBookingNotPending)
+
+	deleteRoom(b.RoomID, t)
+}
@@ -1,24 +1,29 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"time"
 
 	"github.com/ibrat-muslim/booking-service/storage/repo"
-	"github.com/jmoiron/sqlx"
 )
 
 type postRepo struct {
-	db *sqlx.DB
+	db      DB
+	timeout time.Duration
 }
 
-func NewPost(db *sqlx.DB) repo.PostStorageI {
+func NewPost(db DB, timeout time.Duration) repo.PostStorageI {
 	return &postRepo{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
-func (pr *postRepo) Create(post *repo.Post) (*repo.Post, error) {
+func (pr *postRepo) Create(ctx context.Context, post *repo.Post) (*repo.Post, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	query := `
 		INSERT INTO posts (
 			title,
@@ -30,7 +35,7 @@ func (pr *postRepo) Create(post *repo.Post) (*repo.Post, error) {
 		RETURNING id, created_at
 	`
 
-	row := pr.db.QueryRow(
+	row := pr.db.QueryRowContext(ctx,
 		query,
 		post.Title,
 		post.Description,
@@ -51,10 +56,13 @@ func (pr *postRepo) Create(post *repo.Post) (*repo.Post, error) {
 	return post, nil
 }
 
-func (pr *postRepo) Get(id int64) (*repo.Post, error) {
+func (pr *postRepo) Get(ctx context.Context, id int64) (*repo.Post, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	queryView := `UPDATE posts SET views_count = views_count + 1 WHERE id = $1`
 
-	_, err := pr.db.Exec(queryView, id)
+	_, err := pr.db.ExecContext(ctx, queryView, id)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +84,7 @@ func (pr *postRepo) Get(id int64) (*repo.Post, error) {
 
 	var result repo.Post
 
-	err = pr.db.Get(&result, query, id)
+	err = pr.db.GetContext(ctx, &result, query, id)
 
 	if err != nil {
 		return nil, err
@@ -85,38 +93,23 @@ func (pr *postRepo) Get(id int64) (*repo.Post, error) {
 	return &result, nil
 }
 
-func (pr *postRepo) GetAll(params *repo.GetPostsParams) (*repo.GetPostsResult, error) {
+func (pr *postRepo) GetAll(ctx context.Context, params *repo.GetPostsParams) (*repo.GetPostsResult, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	result := repo.GetPostsResult{
 		Posts: make([]*repo.Post, 0),
 		Count: 0,
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
-
-	filter := "WHERE true"
-
-	if params.Search != "" {
-		str := "%" + params.Search + "%"
-		filter += fmt.Sprintf(" AND title ILIKE '%s' ", str)
-	}
-
-	if params.UserID != 0 {
-		filter += fmt.Sprintf(" AND user_id = %d ", params.UserID)
-	}
-
-	if params.CategoryID != 0 {
-		filter += fmt.Sprintf(" AND category_id = %d ", params.CategoryID)
-	}
-
-	orderBy := " ORDER BY created_at DESC "
-
-	if params.SortByDate != "" {
-		orderBy = fmt.Sprintf(" ORDER BY created_at %s ", params.SortByDate)
-	}
+	q := newListQuery().
+		Search(params.Search, "title").
+		Equal("user_id", params.UserID).
+		Equal("category_id", params.CategoryID).
+		OrderBy("created_at", params.SortByDate).
+		Paginate(int64(params.Page), int64(params.Limit))
 
-	query := `
+	query, args := q.Select(`
 		SELECT
 			id,
 			title,
@@ -128,17 +121,17 @@ func (pr *postRepo) GetAll(params *repo.GetPostsParams) (*repo.GetPostsResult, e
 			updated_at,
 			views_count
 		FROM posts
-		` + filter + orderBy + limit
+	`)
 
-	err := pr.db.Select(&result.Posts, query)
+	err := pr.db.SelectContext(ctx, &result.Posts, query, args...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	queryCount := `SELECT count(1) FROM posts ` + filter
+	queryCount, args := q.Count(`SELECT count(1) FROM posts `)
 
-	err = pr.db.Get(&result.Count, queryCount)
+	err = pr.db.GetContext(ctx, &result.Count, queryCount, args...)
 
 	if err != nil {
 		return nil, err
@@ -147,7 +140,10 @@ func (pr *postRepo) GetAll(params *repo.GetPostsParams) (*repo.GetPostsResult, e
 	return &result, nil
 }
 
-func (pr *postRepo) Update(post *repo.Post) error {
+func (pr *postRepo) Update(ctx context.Context, post *repo.Post) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	query := `
 		UPDATE posts SET
 			title = $1,
@@ -158,7 +154,7 @@ func (pr *postRepo) Update(post *repo.Post) error {
 		WHERE id = $6
 	`
 
-	result, err := pr.db.Exec(
+	result, err := pr.db.ExecContext(ctx,
 		query,
 		post.Title,
 		post.Description,
@@ -185,10 +181,30 @@ func (pr *postRepo) Update(post *repo.Post) error {
 	return nil
 }
 
-func (pr *postRepo) Delete(id int64) error {
+func (pr *postRepo) GetAuthorID(ctx context.Context, id int64) (int64, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `SELECT user_id FROM posts WHERE id = $1`
+
+	var userID int64
+
+	err := pr.db.GetContext(ctx, &userID, query, id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (pr *postRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
 	query := `DELETE FROM posts WHERE id = $1`
 
-	result, err := pr.db.Exec(query, id)
+	result, err := pr.db.ExecContext(ctx, query, id)
 
 	if err != nil {
 		return err
@@ -12,7 +12,7 @@ func createPost(t *testing.T) *repo.Post {
 	user := createUser(t)
 	category := createCategory(t)
 
-	post, err := strg.Post().Create(&repo.Post{
+	post, err := strg.Post().Create(ctx, &repo.Post{
 		Title:       faker.Sentence(),
 		Description: faker.Sentence(),
 		UserID:      user.ID,
@@ -26,7 +26,7 @@ func createPost(t *testing.T) *repo.Post {
 }
 
 func deletePost(id int64, t *testing.T) {
-	err := strg.Post().Delete(id)
+	err := strg.Post().Delete(ctx, id)
 	require.NoError(t, err)
 }
 
@@ -38,17 +38,27 @@ func TestCreatePost(t *testing.T) {
 func TestGetPost(t *testing.T) {
 	p := createPost(t)
 
-	post, err := strg.Post().Get(p.ID)
+	post, err := strg.Post().Get(ctx, p.ID)
 	require.NoError(t, err)
 	require.NotEmpty(t, post)
 
 	deletePost(post.ID, t)
 }
 
+func TestGetPostAuthorID(t *testing.T) {
+	p := createPost(t)
+
+	userID, err := strg.Post().GetAuthorID(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.UserID, userID)
+
+	deletePost(p.ID, t)
+}
+
 func TestGetAllPosts(t *testing.T) {
 	p := createPost(t)
 
-	posts, err := strg.Post().GetAll(&repo.GetPostsParams{
+	posts, err := strg.Post().GetAll(ctx, &repo.GetPostsParams{
 		Limit:  10,
 		Page:   1,
 		Search: p.Title,
@@ -61,6 +71,33 @@ func TestGetAllPosts(t *testing.T) {
 	deletePost(p.ID, t)
 }
 
+func TestGetAllPostsHostileSearch(t *testing.T) {
+	p := createPost(t)
+
+	for _, search := range []string{
+		`'; DROP TABLE posts; --`,
+		`%' OR '1'='1`,
+		`%`,
+	} {
+		posts, err := strg.Post().GetAll(ctx, &repo.GetPostsParams{
+			Limit:      10,
+			Page:       1,
+			Search:     search,
+			SortByDate: "asc; DROP TABLE posts; --",
+		})
+
+		require.NoError(t, err)
+		require.Empty(t, posts.Posts)
+		require.Zero(t, posts.Count)
+	}
+
+	post, err := strg.Post().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.ID, post.ID)
+
+	deletePost(p.ID, t)
+}
+
 func TestUpdatePost(t *testing.T) {
 	p := createPost(t)
 	category := createCategory(t)
@@ -69,7 +106,7 @@ func TestUpdatePost(t *testing.T) {
 	p.Description = faker.Sentence()
 	p.CategoryID = category.ID
 
-	err := strg.Post().Update(p)
+	err := strg.Post().Update(ctx, p)
 	require.NoError(t, err)
 
 	deletePost(p.ID, t)
@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type pricingRuleRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewPricingRule(db DB, timeout time.Duration) repo.PricingRuleStorageI {
+	return &pricingRuleRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (prr *pricingRuleRepo) Create(ctx context.Context, rule *repo.PricingRule) (*repo.PricingRule, error) {
+	ctx, cancel := withTimeout(ctx, prr.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO pricing_rules (
+			room_id,
+			type,
+			start_date,
+			end_date,
+			days_of_week,
+			price,
+			multiplier,
+			min_nights,
+			discount_percent
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+
+	row := prr.db.QueryRowContext(ctx,
+		query,
+		rule.RoomID,
+		rule.Type,
+		rule.StartDate,
+		rule.EndDate,
+		pq.Array(rule.DaysOfWeek),
+		rule.Price,
+		rule.Multiplier,
+		rule.MinNights,
+		rule.DiscountPercent,
+	)
+
+	err := row.Scan(
+		&rule.ID,
+		&rule.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (prr *pricingRuleRepo) Get(ctx context.Context, id int64) (*repo.PricingRule, error) {
+	ctx, cancel := withTimeout(ctx, prr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			room_id,
+			type,
+			start_date,
+			end_date,
+			days_of_week,
+			price,
+			multiplier,
+			min_nights,
+			discount_percent,
+			created_at
+		FROM pricing_rules
+		WHERE id = $1
+	`
+
+	rows, err := prr.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := scanPricingRules(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	return result[0], nil
+}
+
+func (prr *pricingRuleRepo) GetAll(ctx context.Context, roomID int64) ([]*repo.PricingRule, error) {
+	ctx, cancel := withTimeout(ctx, prr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			room_id,
+			type,
+			start_date,
+			end_date,
+			days_of_week,
+			price,
+			multiplier,
+			min_nights,
+			discount_percent,
+			created_at
+		FROM pricing_rules
+		WHERE room_id = $1
+		ORDER BY id
+	`
+
+	rows, err := prr.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPricingRules(rows)
+}
+
+func (prr *pricingRuleRepo) GetByRooms(ctx context.Context, roomIDs []int64) ([]*repo.PricingRule, error) {
+	ctx, cancel := withTimeout(ctx, prr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			room_id,
+			type,
+			start_date,
+			end_date,
+			days_of_week,
+			price,
+			multiplier,
+			min_nights,
+			discount_percent,
+			created_at
+		FROM pricing_rules
+		WHERE room_id = ANY($1)
+		ORDER BY room_id, id
+	`
+
+	rows, err := prr.db.QueryContext(ctx, query, pq.Array(roomIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanPricingRules(rows)
+}
+
+func (prr *pricingRuleRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, prr.timeout)
+	defer cancel()
+
+	query := `DELETE FROM pricing_rules WHERE id = $1`
+
+	result, err := prr.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanPricingRules(rows *sql.Rows) ([]*repo.PricingRule, error) {
+	defer rows.Close()
+
+	result := make([]*repo.PricingRule, 0)
+
+	for rows.Next() {
+		var rule repo.PricingRule
+
+		err := rows.Scan(
+			&rule.ID,
+			&rule.RoomID,
+			&rule.Type,
+			&rule.StartDate,
+			&rule.EndDate,
+			pq.Array(&rule.DaysOfWeek),
+			&rule.Price,
+			&rule.Multiplier,
+			&rule.MinNights,
+			&rule.DiscountPercent,
+			&rule.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &rule)
+	}
+
+	return result, rows.Err()
+}
+
+// roomMinNightsFilter returns a condition which holds when no min_nights rule of
+// the room aliased r rejects the stay given by the checkIn and checkOut placeholders
+func roomMinNightsFilter(checkIn, checkOut int) string {
+	return fmt.Sprintf(`NOT EXISTS (
+		SELECT 1 FROM pricing_rules pr
+		WHERE pr.room_id = r.id
+			AND pr.type = 'min_nights'
+			AND pr.min_nights > $%d::DATE - $%d::DATE
+			AND (pr.start_date IS NULL OR $%d::DATE BETWEEN pr.start_date AND pr.end_date)
+	)`, checkOut, checkIn, checkIn)
+}
@@ -0,0 +1,94 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func createPricingRule(t *testing.T) *repo.PricingRule {
+	room := createRoom(t)
+	multiplier := 1.2
+
+	rule, err := strg.PricingRule().Create(ctx, &repo.PricingRule{
+		RoomID:     room.ID,
+		Type:       repo.PricingRuleTypeWeekday,
+		DaysOfWeek: []int64{5, 6},
+		Multiplier: &multiplier,
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, rule)
+
+	return rule
+}
+
+func deletePricingRule(id int64, t *testing.T) {
+	err := strg.PricingRule().Delete(ctx, id)
+	require.NoError(t, err)
+}
+
+func TestCreatePricingRule(t *testing.T) {
+	r := createPricingRule(t)
+	deletePricingRule(r.ID, t)
+}
+
+func TestGetPricingRule(t *testing.T) {
+	r := createPricingRule(t)
+
+	rule, err := strg.PricingRule().Get(ctx, r.ID)
+	require.NoError(t, err)
+	require.Equal(t, r.DaysOfWeek, rule.DaysOfWeek)
+	require.Equal(t, *r.Multiplier, *rule.Multiplier)
+	require.Nil(t, rule.StartDate)
+
+	deletePricingRule(rule.ID, t)
+}
+
+func TestGetAllPricingRules(t *testing.T) {
+	r := createPricingRule(t)
+
+	rules, err := strg.PricingRule().GetAll(ctx, r.RoomID)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+
+	deletePricingRule(r.ID, t)
+}
+
+func TestGetPricingRulesByRooms(t *testing.T) {
+	r1 := createPricingRule(t)
+	r2 := createPricingRule(t)
+
+	rules, err := strg.PricingRule().GetByRooms(ctx, []int64{r1.RoomID, r2.RoomID})
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	deletePricingRule(r1.ID, t)
+	deletePricingRule(r2.ID, t)
+}
+
+func TestGetAvailableRoomsMinNights(t *testing.T) {
+	room := createRoom(t)
+	minNights := int32(3)
+
+	_, err := strg.PricingRule().Create(ctx, &repo.PricingRule{
+		RoomID:    room.ID,
+		Type:      repo.PricingRuleTypeMinNights,
+		MinNights: &minNights,
+	})
+	require.NoError(t, err)
+
+	checkIn, checkOut := stayDates(1, 2)
+
+	rooms, err := strg.Room().GetAvailable(ctx, &repo.GetAvailableRoomsParams{
+		PropertyIDs: []int64{room.PropertyID},
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		Guests:      1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rooms, 0)
+
+	deleteRoom(room.ID, t)
+}
@@ -0,0 +1,357 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type propertyRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewProperty(db DB, timeout time.Duration) repo.PropertyStorageI {
+	return &propertyRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (pr *propertyRepo) Create(ctx context.Context, property *repo.Property) (*repo.Property, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO properties (
+			owner_id,
+			title,
+			description,
+			address,
+			city,
+			latitude,
+			longitude,
+			amenities,
+			cancellation_policy,
+			cancellation_tiers
+		) VALUES($1, $2, $3, $4, $5, $6, $7, COALESCE($8, '{}'::TEXT[]), $9, $10)
+		RETURNING id, created_at
+	`
+
+	tiers, err := marshalCancellationTiers(property.CancellationTiers)
+	if err != nil {
+		return nil, err
+	}
+
+	row := pr.db.QueryRowContext(ctx,
+		query,
+		property.OwnerID,
+		property.Title,
+		property.Description,
+		property.Address,
+		property.City,
+		property.Latitude,
+		property.Longitude,
+		pq.Array(property.Amenities),
+		property.CancellationPolicy,
+		tiers,
+	)
+
+	err = row.Scan(
+		&property.ID,
+		&property.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return property, nil
+}
+
+func (pr *propertyRepo) Get(ctx context.Context, id int64) (*repo.Property, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			owner_id,
+			title,
+			description,
+			address,
+			city,
+			latitude,
+			longitude,
+			amenities,
+			cancellation_policy,
+			cancellation_tiers,
+			created_at,
+			updated_at
+		FROM properties
+		WHERE id = $1
+	`
+
+	var (
+		result repo.Property
+		tiers  []byte
+	)
+
+	err := pr.db.QueryRowContext(ctx, query, id).Scan(
+		&result.ID,
+		&result.OwnerID,
+		&result.Title,
+		&result.Description,
+		&result.Address,
+		&result.City,
+		&result.Latitude,
+		&result.Longitude,
+		pq.Array(&result.Amenities),
+		&result.CancellationPolicy,
+		&tiers,
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result.CancellationTiers, err = unmarshalCancellationTiers(tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (pr *propertyRepo) GetAll(ctx context.Context, params *repo.GetPropertiesParams) (*repo.GetPropertiesResult, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	q := newListQuery().
+		Search(params.Search, "title", "address").
+		Equal("owner_id", params.OwnerID).
+		Paginate(int64(params.Page), int64(params.Limit))
+
+	if params.City != "" {
+		q.Where("lower(city) = lower(?)", params.City)
+	}
+
+	return pr.list(ctx, q)
+}
+
+func (pr *propertyRepo) GetAvailable(ctx context.Context, params *repo.GetAvailablePropertiesParams) (*repo.GetPropertiesResult, error) {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	// The room filters refer to the first args by their placeholders
+	q := newListQuery(
+		pq.Array(repo.ActiveBookingStatuses),
+		params.CheckIn,
+		params.CheckOut,
+		params.Guests,
+	).Where(`EXISTS (
+			SELECT 1 FROM rooms r
+			WHERE r.property_id = properties.id
+				AND r.capacity >= $4
+				AND `+roomFreeFilter(1, 2, 3)+`
+				AND `+roomMinNightsFilter(2, 3)+`
+		)`).
+		Paginate(int64(params.Page), int64(params.Limit))
+
+	if params.City != "" {
+		q.Where("lower(city) = lower(?)", params.City)
+	}
+
+	return pr.list(ctx, q)
+}
+
+func (pr *propertyRepo) list(ctx context.Context, q *listQuery) (*repo.GetPropertiesResult, error) {
+	result := repo.GetPropertiesResult{
+		Properties: make([]*repo.Property, 0),
+		Count:      0,
+	}
+
+	query, args := q.OrderBy("created_at", "desc").Select(`
+		SELECT
+			id,
+			owner_id,
+			title,
+			description,
+			address,
+			city,
+			latitude,
+			longitude,
+			amenities,
+			cancellation_policy,
+			cancellation_tiers,
+			created_at,
+			updated_at
+		FROM properties
+	`)
+
+	rows, err := pr.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			property repo.Property
+			tiers    []byte
+		)
+
+		err := rows.Scan(
+			&property.ID,
+			&property.OwnerID,
+			&property.Title,
+			&property.Description,
+			&property.Address,
+			&property.City,
+			&property.Latitude,
+			&property.Longitude,
+			pq.Array(&property.Amenities),
+			&property.CancellationPolicy,
+			&tiers,
+			&property.CreatedAt,
+			&property.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		property.CancellationTiers, err = unmarshalCancellationTiers(tiers)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Properties = append(result.Properties, &property)
+	}
+
+	queryCount, args := q.Count(`SELECT count(1) FROM properties `)
+
+	err = pr.db.QueryRowContext(ctx, queryCount, args...).Scan(&result.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (pr *propertyRepo) Update(ctx context.Context, property *repo.Property) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE properties SET
+			title = $1,
+			description = $2,
+			address = $3,
+			city = $4,
+			latitude = $5,
+			longitude = $6,
+			amenities = COALESCE($7, '{}'::TEXT[]),
+			cancellation_policy = $8,
+			cancellation_tiers = $9,
+			updated_at = $10
+		WHERE id = $11
+	`
+
+	tiers, err := marshalCancellationTiers(property.CancellationTiers)
+	if err != nil {
+		return err
+	}
+
+	result, err := pr.db.ExecContext(ctx,
+		query,
+		property.Title,
+		property.Description,
+		property.Address,
+		property.City,
+		property.Latitude,
+		property.Longitude,
+		pq.Array(property.Amenities),
+		property.CancellationPolicy,
+		tiers,
+		property.UpdatedAt,
+		property.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (pr *propertyRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, pr.timeout)
+	defer cancel()
+
+	query := `DELETE FROM properties WHERE id = $1`
+
+	result, err := pr.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// marshalCancellationTiers encodes tiers for the cancellation_tiers column,
+// which is NULL for properties without custom tiers
+func marshalCancellationTiers(tiers []*repo.CancellationTier) (interface{}, error) {
+	if len(tiers) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+func unmarshalCancellationTiers(data []byte) ([]*repo.CancellationTier, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var tiers []*repo.CancellationTier
+
+	err := json.Unmarshal(data, &tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	return tiers, nil
+}
@@ -0,0 +1,143 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func createProperty(t *testing.T) *repo.Property {
+	user := createUser(t)
+
+	property, err := strg.Property().Create(ctx, &repo.Property{
+		OwnerID:            user.ID,
+		Title:              faker.Sentence(),
+		Description:        faker.Sentence(),
+		Address:            faker.Sentence(),
+		City:               faker.Word(),
+		Latitude:           41.311081,
+		Longitude:          69.240562,
+		Amenities:          []string{"wifi", "parking"},
+		CancellationPolicy: repo.CancellationPolicyModerate,
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, property)
+
+	return property
+}
+
+func deleteProperty(id int64, t *testing.T) {
+	err := strg.Property().Delete(ctx, id)
+	require.NoError(t, err)
+}
+
+func TestCreateProperty(t *testing.T) {
+	p := createProperty(t)
+	deleteProperty(p.ID, t)
+}
+
+func TestGetProperty(t *testing.T) {
+	p := createProperty(t)
+
+	property, err := strg.Property().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, property)
+	require.Equal(t, p.Amenities, property.Amenities)
+
+	deleteProperty(property.ID, t)
+}
+
+func TestGetAllProperties(t *testing.T) {
+	p := createProperty(t)
+
+	properties, err := strg.Property().GetAll(ctx, &repo.GetPropertiesParams{
+		Limit: 10,
+		Page:  1,
+		City:  p.City,
+	})
+
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(properties.Properties), 1)
+	require.GreaterOrEqual(t, int(properties.Count), 1)
+
+	deleteProperty(p.ID, t)
+}
+
+func TestUpdateProperty(t *testing.T) {
+	p := createProperty(t)
+
+	p.Title = faker.Sentence()
+	p.Description = faker.Sentence()
+	p.Amenities = []string{"pool"}
+
+	err := strg.Property().Update(ctx, p)
+	require.NoError(t, err)
+
+	deleteProperty(p.ID, t)
+}
+
+func TestUpdatePropertyCancellationPolicy(t *testing.T) {
+	p := createProperty(t)
+
+	p.CancellationPolicy = repo.CancellationPolicyCustom
+	p.CancellationTiers = []*repo.CancellationTier{
+		{DaysBefore: 30, RefundPercent: 100},
+		{DaysBefore: 7, RefundPercent: 40},
+	}
+
+	err := strg.Property().Update(ctx, p)
+	require.NoError(t, err)
+
+	property, err := strg.Property().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, repo.CancellationPolicyCustom, property.CancellationPolicy)
+	require.Equal(t, p.CancellationTiers, property.CancellationTiers)
+
+	p.CancellationPolicy = repo.CancellationPolicyCustom
+	p.CancellationTiers = nil
+
+	err = strg.Property().Update(ctx, p)
+	require.Error(t, err)
+
+	deleteProperty(p.ID, t)
+}
+
+func TestDeleteProperty(t *testing.T) {
+	p := createProperty(t)
+	deleteProperty(p.ID, t)
+}
+
+func TestGetAvailableProperties(t *testing.T) {
+	b := createBooking(t)
+	room, err := strg.Room().Get(ctx, b.RoomID)
+	require.NoError(t, err)
+	property, err := strg.Property().Get(ctx, room.PropertyID)
+	require.NoError(t, err)
+
+	properties, err := strg.Property().GetAvailable(ctx, &repo.GetAvailablePropertiesParams{
+		Limit:    10,
+		Page:     1,
+		City:     property.City,
+		CheckIn:  b.CheckIn,
+		CheckOut: b.CheckOut,
+		Guests:   1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, int(properties.Count))
+
+	properties, err = strg.Property().GetAvailable(ctx, &repo.GetAvailablePropertiesParams{
+		Limit:    10,
+		Page:     1,
+		City:     property.City,
+		CheckIn:  b.CheckOut,
+		CheckOut: b.CheckOut.AddDate(0, 0, 1),
+		Guests:   1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, int(properties.Count))
+
+	deleteProperty(property.ID, t)
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+)
+
+// listQuery builds the filter, sort and pagination clauses of list queries.
+// Values are never spliced into the SQL: every condition refers to them with
+// $n placeholders and they are passed to the driver as args.
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+	orderBy    string
+	paginated  bool
+	limit      int64
+	offset     int64
+}
+
+// newListQuery creates a list query whose first placeholders are taken by
+// args, for queries which refer to them with fixed placeholders
+func newListQuery(args ...interface{}) *listQuery {
+	return &listQuery{
+		args: args,
+	}
+}
+
+// Where adds a condition. Each ? in the condition is replaced with the
+// placeholder of the next arg.
+func (q *listQuery) Where(condition string, args ...interface{}) *listQuery {
+	var sb strings.Builder
+
+	for _, arg := range args {
+		i := strings.IndexByte(condition, '?')
+		if i < 0 {
+			break
+		}
+
+		sb.WriteString(condition[:i])
+		sb.WriteString(q.arg(arg))
+		condition = condition[i+1:]
+	}
+	sb.WriteString(condition)
+
+	q.conditions = append(q.conditions, sb.String())
+	return q
+}
+
+// Equal adds a condition that column equals value, unless value is the zero
+// value which stands for no filter
+func (q *listQuery) Equal(column string, value interface{}) *listQuery {
+	switch v := value.(type) {
+	case int64:
+		if v == 0 {
+			return q
+		}
+	case int32:
+		if v == 0 {
+			return q
+		}
+	case string:
+		if v == "" {
+			return q
+		}
+	}
+
+	return q.Where(column+" = ?", value)
+}
+
+// Search adds a condition that any of the columns contains term. Wildcards
+// in term are matched literally.
+func (q *listQuery) Search(term string, columns ...string) *listQuery {
+	if term == "" || len(columns) == 0 {
+		return q
+	}
+
+	placeholder := q.arg("%" + escapeLike(term) + "%")
+
+	conditions := make([]string, len(columns))
+	for i, column := range columns {
+		conditions[i] = column + " ILIKE " + placeholder
+	}
+
+	q.conditions = append(q.conditions, "("+strings.Join(conditions, " OR ")+")")
+	return q
+}
+
+// OrderBy sorts by column, which must not come from the request. Directions
+// other than asc and desc fall back to desc.
+func (q *listQuery) OrderBy(column, direction string) *listQuery {
+	direction = strings.ToUpper(direction)
+	if direction != "ASC" {
+		direction = "DESC"
+	}
+
+	q.orderBy = column + " " + direction
+	return q
+}
+
+// Paginate limits the query to the page, counting from 1
+func (q *listQuery) Paginate(page, limit int64) *listQuery {
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 0 {
+		limit = 0
+	}
+
+	q.paginated = true
+	q.limit = limit
+	q.offset = (page - 1) * limit
+	return q
+}
+
+// Filter returns the WHERE clause, or an empty string without conditions
+func (q *listQuery) Filter() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(q.conditions, " AND ") + " "
+}
+
+// Args returns the args of the filter
+func (q *listQuery) Args() []interface{} {
+	return q.args
+}
+
+// Select appends the filter, sort and pagination clauses to query and
+// returns it with its args
+func (q *listQuery) Select(query string) (string, []interface{}) {
+	args := q.args
+
+	query += q.Filter()
+
+	if q.orderBy != "" {
+		query += " ORDER BY " + q.orderBy + " "
+	}
+
+	if q.paginated {
+		args = append(args[:len(args):len(args)], q.limit, q.offset)
+		query += " LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args)) + " "
+	}
+
+	return query, args
+}
+
+// Count appends the filter to query, which counts the matching rows, and
+// returns it with its args
+func (q *listQuery) Count(query string) (string, []interface{}) {
+	return query + q.Filter(), q.args
+}
+
+func (q *listQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes the wildcards of a LIKE pattern
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var hostileSearches = []string{
+	`'; DROP TABLE posts; --`,
+	`%' OR '1'='1`,
+	`\'; SELECT pg_sleep(10); --`,
+	`$1`,
+	`?`,
+}
+
+func TestListQuerySearch(t *testing.T) {
+	for _, search := range hostileSearches {
+		q := newListQuery().Search(search, "title", "address")
+
+		query, args := q.Select("SELECT id FROM properties")
+
+		require.Equal(t, "SELECT id FROM properties WHERE (title ILIKE $1 OR address ILIKE $1) ", query)
+		require.Equal(t, []interface{}{"%" + escapeLike(search) + "%"}, args)
+	}
+}
+
+func TestListQueryEscapesWildcards(t *testing.T) {
+	require.Equal(t, `100\%`, escapeLike("100%"))
+	require.Equal(t, `a\_b`, escapeLike("a_b"))
+	require.Equal(t, `c:\\dir`, escapeLike(`c:\dir`))
+}
+
+func TestListQueryWhere(t *testing.T) {
+	q := newListQuery("fixed").
+		Where("room_id = ?", int64(1)).
+		Where("end_date > ? AND start_date < ?", "from", "to").
+		Equal("status", "").
+		Equal("owner_id", int64(0)).
+		Equal("guest_id", int64(7))
+
+	require.Equal(t, " WHERE room_id = $2 AND end_date > $3 AND start_date < $4 AND guest_id = $5 ", q.Filter())
+	require.Equal(t, []interface{}{"fixed", int64(1), "from", "to", int64(7)}, q.Args())
+}
+
+func TestListQueryOrderBy(t *testing.T) {
+	for direction, expected := range map[string]string{
+		"asc":                  "ASC",
+		"DESC":                 "DESC",
+		"":                     "DESC",
+		"asc; DROP TABLE x --": "DESC",
+	} {
+		query, _ := newListQuery().OrderBy("created_at", direction).Select("SELECT id FROM posts")
+		require.Equal(t, "SELECT id FROM posts ORDER BY created_at "+expected+" ", query)
+	}
+}
+
+func TestListQueryPaginate(t *testing.T) {
+	q := newListQuery().
+		Equal("user_id", int64(3)).
+		Paginate(3, 10)
+
+	query, args := q.Select("SELECT id FROM posts")
+	require.Equal(t, "SELECT id FROM posts WHERE user_id = $1  LIMIT $2 OFFSET $3 ", query)
+	require.Equal(t, []interface{}{int64(3), int64(10), int64(20)}, args)
+
+	// The count is not paginated
+	query, args = q.Count("SELECT count(1) FROM posts")
+	require.Equal(t, "SELECT count(1) FROM posts WHERE user_id = $1 ", query)
+	require.Equal(t, []interface{}{int64(3)}, args)
+
+	_, args = newListQuery().Paginate(0, -5).Select("SELECT id FROM posts")
+	require.Equal(t, []interface{}{int64(0), int64(0)}, args)
+}
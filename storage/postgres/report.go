@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type reportRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewReport(db DB, timeout time.Duration) repo.ReportStorageI {
+	return &reportRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// GetPropertyMonthly reports every property matching params for every month
+// overlapping the period. Nights and revenue of stays spanning several months
+// are split between them pro rata, while bookings, cancellations and lead time
+// are attributed to the month of check-in.
+func (rr *reportRepo) GetPropertyMonthly(ctx context.Context, params *repo.GetReportParams) ([]*repo.PropertyMonthReport, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	result := make([]*repo.PropertyMonthReport, 0)
+
+	// The report refers to the first args by their placeholders
+	q := newListQuery(
+		params.From,
+		params.To,
+		pq.Array(repo.RevenueBookingStatuses),
+		repo.BookingStatusCancelled,
+	).
+		Equal("p.owner_id", params.OwnerID).
+		Equal("p.id", params.PropertyID)
+
+	query := `
+		WITH months AS (
+			SELECT
+				m::DATE AS month,
+				GREATEST(m::DATE, $1::DATE) AS period_start,
+				LEAST((m + INTERVAL '1 month')::DATE, $2::DATE) AS period_end
+			FROM generate_series(date_trunc('month', $1::DATE::TIMESTAMP), ($2::DATE - 1)::TIMESTAMP, INTERVAL '1 month') m
+		),
+		props AS (
+			SELECT
+				p.id,
+				p.title,
+				(SELECT count(1) FROM rooms r WHERE r.property_id = p.id) AS rooms_count
+			FROM properties p
+			` + q.Filter() + `
+		),
+		figures AS (
+			SELECT
+				p.id AS property_id,
+				p.title AS property_title,
+				m.month,
+				p.rooms_count * (m.period_end - m.period_start) AS available_nights,
+				COALESCE(s.booked_nights, 0) AS booked_nights,
+				COALESCE(s.revenue, 0) AS revenue,
+				COALESCE(c.bookings, 0) AS bookings,
+				COALESCE(c.cancellations, 0) AS cancellations,
+				COALESCE(c.lead_time, 0) AS lead_time
+			FROM props p
+			CROSS JOIN months m
+			LEFT JOIN LATERAL (
+				SELECT
+					SUM(LEAST(b.check_out, m.period_end) - GREATEST(b.check_in, m.period_start)) AS booked_nights,
+					SUM(
+						b.total_price
+						* (LEAST(b.check_out, m.period_end) - GREATEST(b.check_in, m.period_start))
+						/ (b.check_out - b.check_in)
+					) AS revenue
+				FROM bookings b
+				INNER JOIN rooms r ON r.id = b.room_id
+				WHERE r.property_id = p.id
+					AND b.status = ANY($3)
+					AND b.check_in < m.period_end
+					AND b.check_out > m.period_start
+			) s ON true
+			LEFT JOIN LATERAL (
+				SELECT
+					COUNT(1) FILTER (WHERE b.status <> $4) AS bookings,
+					COUNT(1) FILTER (WHERE b.status = $4) AS cancellations,
+					AVG(b.check_in - b.created_at::DATE) FILTER (WHERE b.status <> $4) AS lead_time
+				FROM bookings b
+				INNER JOIN rooms r ON r.id = b.room_id
+				WHERE r.property_id = p.id
+					AND b.check_in >= m.period_start
+					AND b.check_in < m.period_end
+			) c ON true
+		)
+		SELECT
+			property_id,
+			property_title,
+			month,
+			available_nights,
+			booked_nights,
+			COALESCE(ROUND(booked_nights::NUMERIC / NULLIF(available_nights, 0), 4), 0) AS occupancy_rate,
+			ROUND(revenue, 2) AS revenue,
+			COALESCE(ROUND(revenue / NULLIF(booked_nights, 0), 2), 0) AS adr,
+			COALESCE(ROUND(revenue / NULLIF(available_nights, 0), 2), 0) AS revpar,
+			bookings,
+			cancellations,
+			ROUND(lead_time, 1) AS average_lead_time
+		FROM figures
+		ORDER BY property_id, month
+	`
+
+	err := rr.db.SelectContext(ctx, &result, query, q.Args()...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
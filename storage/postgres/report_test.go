@@ -0,0 +1,53 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPropertyMonthlyReport(t *testing.T) {
+	b := createBooking(t)
+	propertyID := roomPropertyID(t, b.RoomID)
+
+	err := strg.Booking().UpdateStatus(ctx, &repo.UpdateBookingStatus{
+		ID:     b.ID,
+		Status: repo.BookingStatusConfirmed,
+	})
+	require.NoError(t, err)
+
+	from := time.Date(b.CheckIn.Year(), b.CheckIn.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 2, 0)
+
+	reports, err := strg.Report().GetPropertyMonthly(ctx, &repo.GetReportParams{
+		PropertyID: propertyID,
+		From:       from,
+		To:         to,
+	})
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	var (
+		availableNights int64
+		bookedNights    int64
+		revenue         float64
+		bookings        int64
+	)
+
+	for _, report := range reports {
+		require.Equal(t, propertyID, report.PropertyID)
+		availableNights += report.AvailableNights
+		bookedNights += report.BookedNights
+		revenue += report.Revenue
+		bookings += report.Bookings
+	}
+
+	require.Equal(t, int64(to.Sub(from).Hours()/24), availableNights)
+	require.Equal(t, int64(3), bookedNights)
+	require.InDelta(t, b.TotalPrice, revenue, 0.01)
+	require.Equal(t, int64(1), bookings)
+
+	deleteRoom(b.RoomID, t)
+}
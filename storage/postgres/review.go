@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+// uniqueViolation is the postgres error code raised by unique constraints
+const uniqueViolation = "23505"
+
+type reviewRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewReview(db DB, timeout time.Duration) repo.ReviewStorageI {
+	return &reviewRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (rr *reviewRepo) Create(ctx context.Context, review *repo.Review) (*repo.Review, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO reviews (
+			booking_id,
+			property_id,
+			guest_id,
+			rating,
+			text
+		) VALUES($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	row := rr.db.QueryRowContext(ctx,
+		query,
+		review.BookingID,
+		review.PropertyID,
+		review.GuestID,
+		review.Rating,
+		review.Text,
+	)
+
+	err := row.Scan(
+		&review.ID,
+		&review.CreatedAt,
+	)
+
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, repo.ErrReviewExists
+		}
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (rr *reviewRepo) Get(ctx context.Context, id int64) (*repo.Review, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			booking_id,
+			property_id,
+			guest_id,
+			rating,
+			text,
+			reply,
+			replied_at,
+			created_at
+		FROM reviews
+		WHERE id = $1
+	`
+
+	var result repo.Review
+
+	err := rr.db.GetContext(ctx, &result, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rr *reviewRepo) GetAll(ctx context.Context, params *repo.GetReviewsParams) (*repo.GetReviewsResult, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	result := repo.GetReviewsResult{
+		Reviews: make([]*repo.Review, 0),
+		Count:   0,
+	}
+
+	q := newListQuery().
+		Equal("property_id", params.PropertyID).
+		OrderBy("created_at", "desc").
+		Paginate(int64(params.Page), int64(params.Limit))
+
+	query, args := q.Select(`
+		SELECT
+			id,
+			booking_id,
+			property_id,
+			guest_id,
+			rating,
+			text,
+			reply,
+			replied_at,
+			created_at
+		FROM reviews
+	`)
+
+	err := rr.db.SelectContext(ctx, &result.Reviews, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	queryCount, args := q.Count(`SELECT count(1) FROM reviews `)
+
+	err = rr.db.GetContext(ctx, &result.Count, queryCount, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rr *reviewRepo) Reply(ctx context.Context, req *repo.ReplyReview) error {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE reviews SET
+			reply = $1,
+			replied_at = $2
+		WHERE id = $3 AND reply IS NULL
+	`
+
+	result, err := rr.db.ExecContext(ctx,
+		query,
+		req.Reply,
+		req.RepliedAt,
+		req.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		_, err := rr.Get(ctx, req.ID)
+		if err != nil {
+			return err
+		}
+		return repo.ErrReplyExists
+	}
+
+	return nil
+}
+
+func (rr *reviewRepo) GetRatingSummary(ctx context.Context, propertyID int64) (*repo.RatingSummaryResult, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	var result repo.RatingSummaryResult
+
+	query := `
+		SELECT
+			COALESCE(ROUND(AVG(rating), 2), 0) AS average,
+			COUNT(1) AS count,
+			COUNT(1) FILTER (WHERE rating = 1) AS one_star,
+			COUNT(1) FILTER (WHERE rating = 2) AS two_stars,
+			COUNT(1) FILTER (WHERE rating = 3) AS three_stars,
+			COUNT(1) FILTER (WHERE rating = 4) AS four_stars,
+			COUNT(1) FILTER (WHERE rating = 5) AS five_stars
+		FROM reviews
+		WHERE property_id = $1
+	`
+
+	err := rr.db.GetContext(ctx, &result, query, propertyID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
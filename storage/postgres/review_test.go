@@ -0,0 +1,127 @@
+package postgres_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func createReview(t *testing.T, rating int32) *repo.Review {
+	b := createBooking(t)
+
+	review, err := strg.Review().Create(ctx, &repo.Review{
+		BookingID:  b.ID,
+		PropertyID: roomPropertyID(t, b.RoomID),
+		GuestID:    b.GuestID,
+		Rating:     rating,
+		Text:       faker.Sentence(),
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, review)
+
+	return review
+}
+
+func TestCreateReview(t *testing.T) {
+	r := createReview(t, 4)
+
+	_, err := strg.Review().Create(ctx, &repo.Review{
+		BookingID:  r.BookingID,
+		PropertyID: r.PropertyID,
+		GuestID:    r.GuestID,
+		Rating:     5,
+		Text:       faker.Sentence(),
+	})
+	require.ErrorIs(t, err, repo.ErrReviewExists)
+
+	deleteProperty(r.PropertyID, t)
+}
+
+func TestGetAllReviews(t *testing.T) {
+	r := createReview(t, 5)
+
+	reviews, err := strg.Review().GetAll(ctx, &repo.GetReviewsParams{
+		Limit:      10,
+		Page:       1,
+		PropertyID: r.PropertyID,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, reviews.Reviews, 1)
+	require.Equal(t, 1, int(reviews.Count))
+
+	deleteProperty(r.PropertyID, t)
+}
+
+func TestReplyReview(t *testing.T) {
+	r := createReview(t, 3)
+
+	err := strg.Review().Reply(ctx, &repo.ReplyReview{
+		ID:        r.ID,
+		Reply:     faker.Sentence(),
+		RepliedAt: time.Now(),
+	})
+	require.NoError(t, err)
+
+	review, err := strg.Review().Get(ctx, r.ID)
+	require.NoError(t, err)
+	require.NotNil(t, review.Reply)
+	require.NotNil(t, review.RepliedAt)
+
+	err = strg.Review().Reply(ctx, &repo.ReplyReview{
+		ID:        r.ID,
+		Reply:     faker.Sentence(),
+		RepliedAt: time.Now(),
+	})
+	require.ErrorIs(t, err, repo.ErrReplyExists)
+
+	deleteProperty(r.PropertyID, t)
+}
+
+func TestGetRatingSummary(t *testing.T) {
+	r := createReview(t, 4)
+
+	// A second booking in another room of the same property
+	room, err := strg.Room().Create(ctx, &repo.Room{
+		PropertyID: r.PropertyID,
+		Title:      faker.Word(),
+		Capacity:   2,
+		BasePrice:  100,
+	})
+	require.NoError(t, err)
+
+	checkIn, checkOut := stayDates(1, 2)
+
+	b, err := strg.Booking().Create(ctx, &repo.Booking{
+		RoomID:      room.ID,
+		GuestID:     r.GuestID,
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		GuestsCount: 1,
+		TotalPrice:  200,
+	})
+	require.NoError(t, err)
+
+	_, err = strg.Review().Create(ctx, &repo.Review{
+		BookingID:  b.ID,
+		PropertyID: r.PropertyID,
+		GuestID:    r.GuestID,
+		Rating:     1,
+		Text:       faker.Sentence(),
+	})
+	require.NoError(t, err)
+
+	summary, err := strg.Review().GetRatingSummary(ctx, r.PropertyID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), summary.Count)
+	require.Equal(t, 2.5, summary.Average)
+	require.Equal(t, int64(1), summary.OneStar)
+	require.Equal(t, int64(1), summary.FourStars)
+	require.Equal(t, int64(0), summary.FiveStars)
+
+	deleteProperty(r.PropertyID, t)
+}
@@ -0,0 +1,367 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type roomRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewRoom(db DB, timeout time.Duration) repo.RoomStorageI {
+	return &roomRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (rr *roomRepo) Create(ctx context.Context, room *repo.Room) (*repo.Room, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO rooms (
+			property_id,
+			title,
+			description,
+			capacity,
+			base_price,
+			photos
+		) VALUES($1, $2, $3, $4, $5, COALESCE($6, '{}'::TEXT[]))
+		RETURNING id, created_at
+	`
+
+	row := rr.db.QueryRowContext(ctx,
+		query,
+		room.PropertyID,
+		room.Title,
+		room.Description,
+		room.Capacity,
+		room.BasePrice,
+		pq.Array(room.Photos),
+	)
+
+	err := row.Scan(
+		&room.ID,
+		&room.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return room, nil
+}
+
+func (rr *roomRepo) Get(ctx context.Context, id int64) (*repo.Room, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			id,
+			property_id,
+			title,
+			description,
+			capacity,
+			base_price,
+			photos,
+			created_at,
+			updated_at
+		FROM rooms
+		WHERE id = $1
+	`
+
+	var result repo.Room
+
+	err := rr.db.QueryRowContext(ctx, query, id).Scan(
+		&result.ID,
+		&result.PropertyID,
+		&result.Title,
+		&result.Description,
+		&result.Capacity,
+		&result.BasePrice,
+		pq.Array(&result.Photos),
+		&result.CreatedAt,
+		&result.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rr *roomRepo) GetAll(ctx context.Context, params *repo.GetRoomsParams) (*repo.GetRoomsResult, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	result := repo.GetRoomsResult{
+		Rooms: make([]*repo.Room, 0),
+		Count: 0,
+	}
+
+	q := newListQuery().
+		Equal("property_id", params.PropertyID).
+		OrderBy("created_at", "desc").
+		Paginate(int64(params.Page), int64(params.Limit))
+
+	query, args := q.Select(`
+		SELECT
+			id,
+			property_id,
+			title,
+			description,
+			capacity,
+			base_price,
+			photos,
+			created_at,
+			updated_at
+		FROM rooms
+	`)
+
+	rows, err := rr.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Rooms, err = scanRooms(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCount, args := q.Count(`SELECT count(1) FROM rooms `)
+
+	err = rr.db.QueryRowContext(ctx, queryCount, args...).Scan(&result.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rr *roomRepo) GetAvailable(ctx context.Context, params *repo.GetAvailableRoomsParams) ([]*repo.Room, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		SELECT
+			r.id,
+			r.property_id,
+			r.title,
+			r.description,
+			r.capacity,
+			r.base_price,
+			r.photos,
+			r.created_at,
+			r.updated_at
+		FROM rooms r
+		WHERE r.property_id = ANY($4)
+			AND r.capacity >= $5
+			AND ` + roomFreeFilter(1, 2, 3) + `
+			AND ` + roomMinNightsFilter(2, 3) + `
+		ORDER BY r.property_id, r.base_price
+	`
+
+	rows, err := rr.db.QueryContext(ctx,
+		query,
+		pq.Array(repo.ActiveBookingStatuses),
+		params.CheckIn,
+		params.CheckOut,
+		pq.Array(params.PropertyIDs),
+		params.Guests,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanRooms(rows)
+}
+
+func (rr *roomRepo) GetCalendar(ctx context.Context, params *repo.GetRoomCalendarParams) ([]*repo.RoomCalendarDay, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	result := make([]*repo.RoomCalendarDay, 0)
+
+	query := `
+		SELECT
+			d::DATE AS date,
+			NOT EXISTS (
+				SELECT 1 FROM bookings b
+				WHERE b.room_id = r.id
+					AND b.status = ANY($2)
+					AND b.check_in <= d::DATE AND b.check_out > d::DATE
+			) AND NOT EXISTS (
+				SELECT 1 FROM room_blocks rb
+				WHERE rb.room_id = r.id
+					AND rb.start_date <= d::DATE AND rb.end_date > d::DATE
+			) AS available,
+			r.base_price AS price
+		FROM rooms r
+		CROSS JOIN generate_series($3::DATE, $4::DATE - 1, INTERVAL '1 day') d
+		WHERE r.id = $1
+		ORDER BY d
+	`
+
+	err := rr.db.SelectContext(ctx,
+		&result,
+		query,
+		params.RoomID,
+		pq.Array(repo.ActiveBookingStatuses),
+		params.From,
+		params.To,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rr *roomRepo) Update(ctx context.Context, room *repo.Room) error {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE rooms SET
+			title = $1,
+			description = $2,
+			capacity = $3,
+			base_price = $4,
+			photos = COALESCE($5, '{}'::TEXT[]),
+			updated_at = $6
+		WHERE id = $7
+	`
+
+	result, err := rr.db.ExecContext(ctx,
+		query,
+		room.Title,
+		room.Description,
+		room.Capacity,
+		room.BasePrice,
+		pq.Array(room.Photos),
+		room.UpdatedAt,
+		room.ID,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (rr *roomRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `DELETE FROM rooms WHERE id = $1`
+
+	result, err := rr.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanRooms(rows *sql.Rows) ([]*repo.Room, error) {
+	defer rows.Close()
+
+	result := make([]*repo.Room, 0)
+
+	for rows.Next() {
+		var room repo.Room
+
+		err := rows.Scan(
+			&room.ID,
+			&room.PropertyID,
+			&room.Title,
+			&room.Description,
+			&room.Capacity,
+			&room.BasePrice,
+			pq.Array(&room.Photos),
+			&room.CreatedAt,
+			&room.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &room)
+	}
+
+	return result, rows.Err()
+}
+
+func (rr *roomRepo) SetCalendarToken(ctx context.Context, id int64, token string) error {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `UPDATE rooms SET calendar_token = $1 WHERE id = $2`
+
+	result, err := rr.db.ExecContext(ctx, query, token, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (rr *roomRepo) GetCalendarToken(ctx context.Context, id int64) (*string, error) {
+	ctx, cancel := withTimeout(ctx, rr.timeout)
+	defer cancel()
+
+	query := `SELECT calendar_token FROM rooms WHERE id = $1`
+
+	var token *string
+
+	err := rr.db.GetContext(ctx, &token, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
@@ -0,0 +1,296 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+)
+
+type roomBlockRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewRoomBlock(db DB, timeout time.Duration) repo.RoomBlockStorageI {
+	return &roomBlockRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (rb *roomBlockRepo) Create(ctx context.Context, block *repo.RoomBlock) (*repo.RoomBlock, error) {
+	ctx, cancel := withTimeout(ctx, rb.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, rb.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	// Lock the room like booking creation does, so that a stay cannot be
+	// reserved over the block while it is being created
+	var roomID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM rooms WHERE id = $1 FOR UPDATE`, block.RoomID).Scan(&roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	queryOverlap := `
+		SELECT count(1) FROM bookings
+		WHERE room_id = $1
+			AND status = ANY($2)
+			AND daterange(check_in, check_out) && daterange($3::DATE, $4::DATE)
+	`
+
+	var overlaps int64
+	err = tx.QueryRowContext(ctx,
+		queryOverlap,
+		block.RoomID,
+		pq.Array(repo.ActiveBookingStatuses),
+		block.StartDate,
+		block.EndDate,
+	).Scan(&overlaps)
+	if err != nil {
+		return nil, err
+	}
+
+	if overlaps > 0 {
+		return nil, repo.ErrRoomNotAvailable
+	}
+
+	query := `
+		INSERT INTO room_blocks (
+			room_id,
+			start_date,
+			end_date,
+			reason,
+			source
+		) VALUES($1, $2, $3, $4, $5)
+		RETURNING id, source, created_at
+	`
+
+	row := tx.QueryRowContext(ctx,
+		query,
+		block.RoomID,
+		block.StartDate,
+		block.EndDate,
+		block.Reason,
+		repo.RoomBlockSourceManual,
+	)
+
+	err = row.Scan(
+		&block.ID,
+		&block.Source,
+		&block.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+func (rb *roomBlockRepo) Get(ctx context.Context, id int64) (*repo.RoomBlock, error) {
+	ctx, cancel := withTimeout(ctx, rb.timeout)
+	defer cancel()
+
+	var result repo.RoomBlock
+
+	query := `
+		SELECT
+			id,
+			room_id,
+			start_date,
+			end_date,
+			reason,
+			source,
+			uid,
+			created_at,
+			updated_at
+		FROM room_blocks
+		WHERE id = $1
+	`
+
+	err := rb.db.GetContext(ctx, &result, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rb *roomBlockRepo) GetAll(ctx context.Context, params *repo.GetRoomBlocksParams) ([]*repo.RoomBlock, error) {
+	ctx, cancel := withTimeout(ctx, rb.timeout)
+	defer cancel()
+
+	result := make([]*repo.RoomBlock, 0)
+
+	q := newListQuery().
+		Where("room_id = ?", params.RoomID).
+		OrderBy("start_date", "asc")
+
+	if !params.From.IsZero() {
+		q.Where("end_date > ?", params.From)
+	}
+
+	query, args := q.Select(`
+		SELECT
+			id,
+			room_id,
+			start_date,
+			end_date,
+			reason,
+			source,
+			uid,
+			created_at,
+			updated_at
+		FROM room_blocks
+	`)
+
+	err := rb.db.SelectContext(ctx, &result, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rb *roomBlockRepo) Import(ctx context.Context, req *repo.ImportRoomBlocks) (*repo.ImportRoomBlocksResult, error) {
+	ctx, cancel := withTimeout(ctx, rb.timeout)
+	defer cancel()
+
+	var result repo.ImportRoomBlocksResult
+
+	tx, err := begin(ctx, rb.db)
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	// Lock the room like booking creation does, so that a stay cannot be
+	// reserved while blocks covering it are being imported
+	var roomID int64
+	err = tx.QueryRowContext(ctx, `SELECT id FROM rooms WHERE id = $1 FOR UPDATE`, req.RoomID).Scan(&roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO room_blocks (
+			room_id,
+			start_date,
+			end_date,
+			reason,
+			source,
+			uid
+		) VALUES($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (room_id, uid) DO UPDATE SET
+			start_date = EXCLUDED.start_date,
+			end_date = EXCLUDED.end_date,
+			reason = EXCLUDED.reason,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE (room_blocks.start_date, room_blocks.end_date, room_blocks.reason)
+			IS DISTINCT FROM (EXCLUDED.start_date, EXCLUDED.end_date, EXCLUDED.reason)
+		RETURNING xmax = 0 AS inserted
+	`
+
+	for _, block := range req.Blocks {
+		var inserted bool
+
+		err = tx.QueryRowContext(ctx,
+			query,
+			req.RoomID,
+			block.StartDate,
+			block.EndDate,
+			block.Reason,
+			repo.RoomBlockSourceICal,
+			block.UID,
+		).Scan(&inserted)
+
+		switch {
+		case err == nil && inserted:
+			result.Created++
+		case err == nil:
+			result.Updated++
+		case errors.Is(err, sql.ErrNoRows):
+			result.Unchanged++
+		default:
+			return nil, err
+		}
+	}
+
+	if len(req.CancelledUIDs) > 0 {
+		res, err := tx.ExecContext(ctx,
+			`DELETE FROM room_blocks WHERE room_id = $1 AND source = $2 AND uid = ANY($3)`,
+			req.RoomID,
+			repo.RoomBlockSourceICal,
+			pq.Array(req.CancelledUIDs),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		removed, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		result.Removed = int32(removed)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (rb *roomBlockRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, rb.timeout)
+	defer cancel()
+
+	query := `DELETE FROM room_blocks WHERE id = $1`
+
+	result, err := rb.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// roomBlockedFilter returns a condition which holds when a block of the room
+// aliased r overlaps the stay given by the checkIn and checkOut placeholders
+func roomBlockedFilter(checkIn, checkOut int) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM room_blocks rb
+		WHERE rb.room_id = r.id
+			AND daterange(rb.start_date, rb.end_date) && daterange($%d::DATE, $%d::DATE)
+	)`, checkIn, checkOut)
+}
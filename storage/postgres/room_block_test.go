@@ -0,0 +1,141 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func importRoomBlock(t *testing.T, roomID int64, uid string, fromNow, nights int) *repo.ImportRoomBlocksResult {
+	start, end := stayDates(fromNow, nights)
+
+	result, err := strg.RoomBlock().Import(ctx, &repo.ImportRoomBlocks{
+		RoomID: roomID,
+		Blocks: []*repo.RoomBlock{
+			{
+				StartDate: start,
+				EndDate:   end,
+				Reason:    "Reserved",
+				UID:       &uid,
+			},
+		},
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, result)
+
+	return result
+}
+
+func TestImportRoomBlocks(t *testing.T) {
+	room := createRoom(t)
+
+	result := importRoomBlock(t, room.ID, "abc@other-channel", 1, 3)
+	require.Equal(t, int32(1), result.Created)
+
+	result = importRoomBlock(t, room.ID, "abc@other-channel", 1, 3)
+	require.Equal(t, int32(1), result.Unchanged)
+
+	result = importRoomBlock(t, room.ID, "abc@other-channel", 2, 3)
+	require.Equal(t, int32(1), result.Updated)
+
+	blocks, err := strg.RoomBlock().GetAll(ctx, &repo.GetRoomBlocksParams{
+		RoomID: room.ID,
+	})
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	require.Equal(t, repo.RoomBlockSourceICal, blocks[0].Source)
+
+	result, err = strg.RoomBlock().Import(ctx, &repo.ImportRoomBlocks{
+		RoomID:        room.ID,
+		CancelledUIDs: []string{"abc@other-channel"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result.Removed)
+
+	deleteRoom(room.ID, t)
+}
+
+func TestRoomBlockAvailability(t *testing.T) {
+	room := createRoom(t)
+	guest := createUser(t)
+
+	importRoomBlock(t, room.ID, "abc@other-channel", 1, 3)
+
+	checkIn, checkOut := stayDates(2, 2)
+
+	_, err := strg.Booking().Create(ctx, &repo.Booking{
+		RoomID:      room.ID,
+		GuestID:     guest.ID,
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		GuestsCount: 1,
+		TotalPrice:  200,
+	})
+	require.ErrorIs(t, err, repo.ErrRoomNotAvailable)
+
+	rooms, err := strg.Room().GetAvailable(ctx, &repo.GetAvailableRoomsParams{
+		PropertyIDs: []int64{room.PropertyID},
+		CheckIn:     checkIn,
+		CheckOut:    checkOut,
+		Guests:      1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rooms, 0)
+
+	deleteRoom(room.ID, t)
+}
+
+func TestRoomCalendarToken(t *testing.T) {
+	room := createRoom(t)
+
+	token, err := strg.Room().GetCalendarToken(ctx, room.ID)
+	require.NoError(t, err)
+	require.Nil(t, token)
+
+	err = strg.Room().SetCalendarToken(ctx, room.ID, "secret")
+	require.NoError(t, err)
+
+	token, err = strg.Room().GetCalendarToken(ctx, room.ID)
+	require.NoError(t, err)
+	require.Equal(t, "secret", *token)
+
+	deleteRoom(room.ID, t)
+}
+
+func TestCreateRoomBlock(t *testing.T) {
+	b := createBooking(t)
+
+	// Overlaps the last night of b
+	start := b.CheckOut.AddDate(0, 0, -1)
+
+	_, err := strg.RoomBlock().Create(ctx, &repo.RoomBlock{
+		RoomID:    b.RoomID,
+		StartDate: start,
+		EndDate:   start.AddDate(0, 0, 2),
+		Reason:    "Maintenance",
+	})
+	require.ErrorIs(t, err, repo.ErrRoomNotAvailable)
+
+	block, err := strg.RoomBlock().Create(ctx, &repo.RoomBlock{
+		RoomID:    b.RoomID,
+		StartDate: b.CheckOut,
+		EndDate:   b.CheckOut.AddDate(0, 0, 2),
+		Reason:    "Maintenance",
+	})
+	require.NoError(t, err)
+	require.Equal(t, repo.RoomBlockSourceManual, block.Source)
+
+	got, err := strg.RoomBlock().Get(ctx, block.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Maintenance", got.Reason)
+
+	err = strg.RoomBlock().Delete(ctx, block.ID)
+	require.NoError(t, err)
+
+	err = strg.RoomBlock().Delete(ctx, block.ID)
+	require.Error(t, err)
+
+	deleteRoom(b.RoomID, t)
+}
@@ -0,0 +1,131 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func createRoom(t *testing.T) *repo.Room {
+	property := createProperty(t)
+
+	room, err := strg.Room().Create(ctx, &repo.Room{
+		PropertyID:  property.ID,
+		Title:       faker.Sentence(),
+		Description: faker.Sentence(),
+		Capacity:    2,
+		BasePrice:   100,
+		Photos:      []string{"/media/room.jpg"},
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, room)
+
+	return room
+}
+
+func deleteRoom(id int64, t *testing.T) {
+	err := strg.Room().Delete(ctx, id)
+	require.NoError(t, err)
+}
+
+func TestCreateRoom(t *testing.T) {
+	r := createRoom(t)
+	deleteRoom(r.ID, t)
+}
+
+func TestGetRoom(t *testing.T) {
+	r := createRoom(t)
+
+	room, err := strg.Room().Get(ctx, r.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, room)
+	require.Equal(t, r.BasePrice, room.BasePrice)
+
+	deleteRoom(room.ID, t)
+}
+
+func TestGetAllRooms(t *testing.T) {
+	r := createRoom(t)
+
+	rooms, err := strg.Room().GetAll(ctx, &repo.GetRoomsParams{
+		Limit:      10,
+		Page:       1,
+		PropertyID: r.PropertyID,
+	})
+
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(rooms.Rooms), 1)
+	require.GreaterOrEqual(t, int(rooms.Count), 1)
+
+	deleteRoom(r.ID, t)
+}
+
+func TestUpdateRoom(t *testing.T) {
+	r := createRoom(t)
+
+	r.Title = faker.Sentence()
+	r.Capacity = 4
+	r.BasePrice = 150
+
+	err := strg.Room().Update(ctx, r)
+	require.NoError(t, err)
+
+	deleteRoom(r.ID, t)
+}
+
+func TestDeleteRoom(t *testing.T) {
+	r := createRoom(t)
+	deleteRoom(r.ID, t)
+}
+
+func TestGetAvailableRooms(t *testing.T) {
+	b := createBooking(t)
+
+	rooms, err := strg.Room().GetAvailable(ctx, &repo.GetAvailableRoomsParams{
+		PropertyIDs: []int64{roomPropertyID(t, b.RoomID)},
+		CheckIn:     b.CheckIn,
+		CheckOut:    b.CheckOut,
+		Guests:      1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rooms, 0)
+
+	rooms, err = strg.Room().GetAvailable(ctx, &repo.GetAvailableRoomsParams{
+		PropertyIDs: []int64{roomPropertyID(t, b.RoomID)},
+		CheckIn:     b.CheckOut,
+		CheckOut:    b.CheckOut.AddDate(0, 0, 2),
+		Guests:      1,
+	})
+	require.NoError(t, err)
+	require.Len(t, rooms, 1)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func TestGetRoomCalendar(t *testing.T) {
+	b := createBooking(t)
+
+	days, err := strg.Room().GetCalendar(ctx, &repo.GetRoomCalendarParams{
+		RoomID: b.RoomID,
+		From:   b.CheckIn.AddDate(0, 0, -1),
+		To:     b.CheckOut.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 5)
+
+	require.True(t, days[0].Available)
+	require.False(t, days[1].Available)
+	require.False(t, days[3].Available)
+	require.True(t, days[4].Available)
+
+	deleteRoom(b.RoomID, t)
+}
+
+func roomPropertyID(t *testing.T, roomID int64) int64 {
+	room, err := strg.Room().Get(ctx, roomID)
+	require.NoError(t, err)
+	return room.PropertyID
+}
@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type sessionRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewSession(db DB, timeout time.Duration) repo.SessionStorageI {
+	return &sessionRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (sr *sessionRepo) Create(ctx context.Context, session *repo.Session) (*repo.Session, error) {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO sessions (
+			id,
+			user_id,
+			refresh_token_hash,
+			user_agent,
+			ip_address,
+			expires_at
+		) VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	row := sr.db.QueryRowContext(ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.RefreshTokenHash,
+		session.UserAgent,
+		session.IPAddress,
+		session.ExpiresAt,
+	)
+
+	err := row.Scan(&session.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (sr *sessionRepo) Get(ctx context.Context, id string) (*repo.Session, error) {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	var result repo.Session
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			refresh_token_hash,
+			user_agent,
+			ip_address,
+			created_at,
+			refreshed_at,
+			expires_at,
+			revoked_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	err := sr.db.GetContext(ctx, &result, query, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (sr *sessionRepo) GetActive(ctx context.Context, userID int64) ([]*repo.Session, error) {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	result := make([]*repo.Session, 0)
+
+	query := `
+		SELECT
+			id,
+			user_id,
+			refresh_token_hash,
+			user_agent,
+			ip_address,
+			created_at,
+			refreshed_at,
+			expires_at,
+			revoked_at
+		FROM sessions
+		WHERE user_id = $1
+			AND revoked_at IS NULL
+			AND expires_at > CURRENT_TIMESTAMP
+		ORDER BY COALESCE(refreshed_at, created_at) DESC
+	`
+
+	err := sr.db.SelectContext(ctx, &result, query, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (sr *sessionRepo) Rotate(ctx context.Context, req *repo.RotateSession) error {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE sessions SET
+			refresh_token_hash = $3,
+			refreshed_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+			AND refresh_token_hash = $2
+			AND revoked_at IS NULL
+			AND expires_at > CURRENT_TIMESTAMP
+	`
+
+	result, err := sr.db.ExecContext(ctx, query, req.ID, req.OldHash, req.NewHash)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (sr *sessionRepo) Revoke(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE sessions SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	result, err := sr.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (sr *sessionRepo) RevokeAll(ctx context.Context, userID int64, keepID string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE sessions SET
+			revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+			AND id::TEXT <> $2
+			AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	result := make([]string, 0)
+
+	err := sr.db.SelectContext(ctx, &result, query, userID, keepID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
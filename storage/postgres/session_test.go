@@ -0,0 +1,85 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func createSession(t *testing.T, userID int64) *repo.Session {
+	session, err := strg.Session().Create(ctx, &repo.Session{
+		ID:               uuid.NewString(),
+		UserID:           userID,
+		RefreshTokenHash: "hash",
+		UserAgent:        "test",
+		IPAddress:        "127.0.0.1",
+		ExpiresAt:        time.Now().Add(time.Hour),
+	})
+
+	require.NoError(t, err)
+	require.NotEmpty(t, session)
+
+	return session
+}
+
+func TestRotateSession(t *testing.T) {
+	user := createUser(t)
+	session := createSession(t, user.ID)
+
+	err := strg.Session().Rotate(ctx, &repo.RotateSession{
+		ID:      session.ID,
+		OldHash: "hash",
+		NewHash: "hash2",
+	})
+	require.NoError(t, err)
+
+	// The old hash has been rotated out
+	err = strg.Session().Rotate(ctx, &repo.RotateSession{
+		ID:      session.ID,
+		OldHash: "hash",
+		NewHash: "hash3",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err := strg.Session().Get(ctx, session.ID)
+	require.NoError(t, err)
+	require.Equal(t, "hash2", got.RefreshTokenHash)
+	require.NotNil(t, got.RefreshedAt)
+}
+
+func TestRevokeSessions(t *testing.T) {
+	user := createUser(t)
+	current := createSession(t, user.ID)
+	other := createSession(t, user.ID)
+	third := createSession(t, user.ID)
+
+	sessions, err := strg.Session().GetActive(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 3)
+
+	err = strg.Session().Revoke(ctx, third.ID)
+	require.NoError(t, err)
+
+	err = strg.Session().Revoke(ctx, third.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	revoked, err := strg.Session().RevokeAll(ctx, user.ID, current.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{other.ID}, revoked)
+
+	sessions, err = strg.Session().GetActive(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	require.Equal(t, current.ID, sessions[0].ID)
+
+	err = strg.Session().Rotate(ctx, &repo.RotateSession{
+		ID:      other.ID,
+		OldHash: "hash",
+		NewHash: "hash2",
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
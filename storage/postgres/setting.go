@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type settingRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewSetting(db DB, timeout time.Duration) repo.SettingStorageI {
+	return &settingRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (sr *settingRepo) Get(ctx context.Context, key string) (string, error) {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	var value string
+
+	err := sr.db.GetContext(ctx, &value, `SELECT value FROM settings WHERE key = $1`, key)
+
+	if err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+func (sr *settingRepo) Set(ctx context.Context, key, value string) error {
+	ctx, cancel := withTimeout(ctx, sr.timeout)
+	defer cancel()
+
+	query := `
+		INSERT INTO settings (key, value) VALUES($1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			value = EXCLUDED.value,
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := sr.db.ExecContext(ctx, query, key, value)
+
+	return err
+}
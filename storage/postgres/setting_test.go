@@ -0,0 +1,24 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetting(t *testing.T) {
+	err := strg.Setting().Set(ctx, repo.SettingTwoFactorRequired, "true")
+	require.NoError(t, err)
+
+	value, err := strg.Setting().Get(ctx, repo.SettingTwoFactorRequired)
+	require.NoError(t, err)
+	require.Equal(t, "true", value)
+
+	err = strg.Setting().Set(ctx, repo.SettingTwoFactorRequired, "false")
+	require.NoError(t, err)
+
+	value, err = strg.Setting().Get(ctx, repo.SettingTwoFactorRequired)
+	require.NoError(t, err)
+	require.Equal(t, "false", value)
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+)
+
+type twoFactorRepo struct {
+	db      DB
+	timeout time.Duration
+}
+
+func NewTwoFactor(db DB, timeout time.Duration) repo.TwoFactorStorageI {
+	return &twoFactorRepo{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+func (tr *twoFactorRepo) Enroll(ctx context.Context, req *repo.EnrollTwoFactor) error {
+	ctx, cancel := withTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, tr.db)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO two_factor (user_id, secret) VALUES($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET
+			secret = EXCLUDED.secret,
+			last_used_step = 0,
+			created_at = CURRENT_TIMESTAMP
+		WHERE two_factor.enabled_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, req.UserID, req.Secret)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return repo.ErrTwoFactorEnabled
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range req.RecoveryCodeHashes {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO recovery_codes (user_id, code_hash) VALUES($1, $2)`,
+			req.UserID,
+			hash,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (tr *twoFactorRepo) Get(ctx context.Context, userID int64) (*repo.TwoFactor, error) {
+	ctx, cancel := withTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	var result repo.TwoFactor
+
+	query := `
+		SELECT
+			user_id,
+			secret,
+			last_used_step,
+			enabled_at,
+			created_at
+		FROM two_factor
+		WHERE user_id = $1
+	`
+
+	err := tr.db.GetContext(ctx, &result, query, userID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (tr *twoFactorRepo) Enable(ctx context.Context, userID, step int64) error {
+	ctx, cancel := withTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE two_factor SET
+			enabled_at = CURRENT_TIMESTAMP,
+			last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NULL
+	`
+
+	return execAffectingRow(ctx, tr.db, query, userID, step)
+}
+
+func (tr *twoFactorRepo) UseStep(ctx context.Context, userID, step int64) error {
+	ctx, cancel := withTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE two_factor SET
+			last_used_step = $2
+		WHERE user_id = $1
+			AND enabled_at IS NOT NULL
+			AND last_used_step < $2
+	`
+
+	return execAffectingRow(ctx, tr.db, query, userID, step)
+}
+
+func (tr *twoFactorRepo) UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error {
+	ctx, cancel := withTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	query := `
+		UPDATE recovery_codes SET
+			used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+			AND code_hash = $2
+			AND used_at IS NULL
+	`
+
+	return execAffectingRow(ctx, tr.db, query, userID, codeHash)
+}
+
+func (tr *twoFactorRepo) Disable(ctx context.Context, userID int64) error {
+	ctx, cancel := withTimeout(ctx, tr.timeout)
+	defer cancel()
+
+	tx, err := begin(ctx, tr.db)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM two_factor WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// execAffectingRow runs the query and returns sql.ErrNoRows if it changed
+// no rows
+func execAffectingRow(ctx context.Context, db DB, query string, args ...interface{}) error {
+	result, err := db.ExecContext(ctx, query, args...)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwoFactor(t *testing.T) {
+	u := createUser(t)
+
+	enroll := repo.EnrollTwoFactor{
+		UserID:             u.ID,
+		Secret:             "JBSWY3DPEHPK3PXP",
+		RecoveryCodeHashes: []string{"hash1", "hash2"},
+	}
+
+	err := strg.TwoFactor().Enroll(ctx, &enroll)
+	require.NoError(t, err)
+
+	// Enrolling again before enabling replaces the secret
+	enroll.Secret = "KRSXG5CTMVRXEZLU"
+	err = strg.TwoFactor().Enroll(ctx, &enroll)
+	require.NoError(t, err)
+
+	tf, err := strg.TwoFactor().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, enroll.Secret, tf.Secret)
+	require.Nil(t, tf.EnabledAt)
+
+	err = strg.TwoFactor().Enable(ctx, u.ID, 100)
+	require.NoError(t, err)
+
+	err = strg.TwoFactor().Enroll(ctx, &enroll)
+	require.ErrorIs(t, err, repo.ErrTwoFactorEnabled)
+
+	// Codes cannot be replayed
+	err = strg.TwoFactor().UseStep(ctx, u.ID, 100)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = strg.TwoFactor().UseStep(ctx, u.ID, 101)
+	require.NoError(t, err)
+
+	err = strg.TwoFactor().UseRecoveryCode(ctx, u.ID, "hash1")
+	require.NoError(t, err)
+
+	err = strg.TwoFactor().UseRecoveryCode(ctx, u.ID, "hash1")
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = strg.TwoFactor().Disable(ctx, u.ID)
+	require.NoError(t, err)
+
+	_, err = strg.TwoFactor().Get(ctx, u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	deleteUser(u.ID, t)
+}
@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// DB runs the queries of the repositories. It is implemented by *sqlx.DB and,
+// for repositories bound to a transaction, by *sqlx.Tx.
+type DB interface {
+	sqlx.ExtContext
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// withTimeout bounds ctx by the query timeout of a repository. A timeout of
+// 0 leaves it unbounded.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+const (
+	// maxTxAttempts is how many times a transaction is run before giving up
+	// on serialization failures
+	maxTxAttempts = 5
+	// txRetryDelay is the delay before the first retry, doubled for every
+	// following one
+	txRetryDelay = 10 * time.Millisecond
+)
+
+// RunInTx runs fn in a serializable transaction, which is committed if fn
+// returns nil and rolled back otherwise. When the transaction fails to
+// serialize with concurrent ones, it is run again. If db already is a
+// transaction, fn joins it and retries are left to the outer one.
+func RunInTx(ctx context.Context, db DB, fn func(tx DB) error) error {
+	conn, ok := db.(*sqlx.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	delay := txRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, conn, fn)
+		if err == nil || !IsSerializationFailure(err) || attempt == maxTxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func runTx(ctx context.Context, db *sqlx.DB, fn func(tx DB) error) error {
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// IsSerializationFailure reports whether err is a serialization failure or
+// a deadlock, after which the transaction can be run again
+func IsSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
+
+// localTx is the transaction of a single repository method. Within an outer
+// transaction the method joins it, and committing or rolling back is left to
+// the outer one.
+type localTx struct {
+	DB
+	tx *sqlx.Tx
+}
+
+// begin starts the transaction of a repository method
+func begin(ctx context.Context, db DB) (*localTx, error) {
+	conn, ok := db.(*sqlx.DB)
+	if !ok {
+		return &localTx{DB: db}, nil
+	}
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localTx{DB: tx, tx: tx}, nil
+}
+
+func (t *localTx) Commit() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+func (t *localTx) Rollback() error {
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Rollback()
+}
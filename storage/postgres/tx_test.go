@@ -0,0 +1,104 @@
+package postgres_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/ibrat-muslim/booking-service/storage"
+	"github.com/ibrat-muslim/booking-service/storage/postgres"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSerializationFailure(t *testing.T) {
+	require.True(t, postgres.IsSerializationFailure(&pq.Error{Code: "40001"}))
+	require.True(t, postgres.IsSerializationFailure(fmt.Errorf("wrapped: %w", &pq.Error{Code: "40P01"})))
+	require.False(t, postgres.IsSerializationFailure(&pq.Error{Code: "23505"}))
+	require.False(t, postgres.IsSerializationFailure(sql.ErrNoRows))
+	require.False(t, postgres.IsSerializationFailure(nil))
+}
+
+func TestWithTxCommit(t *testing.T) {
+	var id int64
+
+	err := strg.WithTx(ctx, func(tx storage.StorageI) error {
+		category, err := tx.Category().Create(ctx, &repo.Category{
+			Title: faker.Word(),
+		})
+		if err != nil {
+			return err
+		}
+
+		id = category.ID
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = strg.Category().Get(ctx, id)
+	require.NoError(t, err)
+
+	deleteCategory(id, t)
+}
+
+func TestWithTxRollback(t *testing.T) {
+	errFailed := errors.New("failed")
+	var id int64
+
+	err := strg.WithTx(ctx, func(tx storage.StorageI) error {
+		category, err := tx.Category().Create(ctx, &repo.Category{
+			Title: faker.Word(),
+		})
+		if err != nil {
+			return err
+		}
+
+		id = category.ID
+		return errFailed
+	})
+	require.ErrorIs(t, err, errFailed)
+
+	_, err = strg.Category().Get(ctx, id)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestConcurrentLikes(t *testing.T) {
+	post := createPost(t)
+	user := createUser(t)
+
+	// An even number of toggles of the same like leaves none
+	const toggles = 4
+
+	errs := make(chan error, toggles)
+
+	var wg sync.WaitGroup
+	for i := 0; i < toggles; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			errs <- storage.ToggleLike(ctx, strg, &repo.Like{
+				PostID: post.ID,
+				UserID: user.ID,
+				Status: true,
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	counts, err := strg.Like().GetLikesDislikesCount(ctx, post.ID)
+	require.NoError(t, err)
+	require.Zero(t, counts.LikesCount)
+
+	deletePost(post.ID, t)
+	deleteUser(user.ID, t)
+}
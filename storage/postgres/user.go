@@ -1,24 +1,29 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"time"
 
 	"github.com/ibrat-muslim/booking-service/storage/repo"
-	"github.com/jmoiron/sqlx"
 )
 
 type userRepo struct {
-	db *sqlx.DB
+	db      DB
+	timeout time.Duration
 }
 
-func NewUser(db *sqlx.DB) repo.UserStorageI {
+func NewUser(db DB, timeout time.Duration) repo.UserStorageI {
 	return &userRepo{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
-func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
+func (ur *userRepo) Create(ctx context.Context, user *repo.User) (*repo.User, error) {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	query := `
 		INSERT INTO users (
 			first_name,
@@ -30,12 +35,13 @@ func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
 			password,
 			profile_image_url,
 			address,
-			type
-		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			type,
+			email_verified_at
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 		RETURNING id, created_at
 	`
 
-	row := ur.db.QueryRow(
+	row := ur.db.QueryRowContext(ctx,
 		query,
 		user.FirstName,
 		user.LastName,
@@ -47,6 +53,7 @@ func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
 		user.ProfileImageUrl,
 		user.Address,
 		user.Type,
+		user.EmailVerifiedAt,
 	)
 
 	err := row.Scan(
@@ -61,7 +68,10 @@ func (ur *userRepo) Create(user *repo.User) (*repo.User, error) {
 	return user, nil
 }
 
-func (ur *userRepo) Get(id int64) (*repo.User, error) {
+func (ur *userRepo) Get(ctx context.Context, id int64) (*repo.User, error) {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	query := `
 		SELECT
 			id,
@@ -75,14 +85,16 @@ func (ur *userRepo) Get(id int64) (*repo.User, error) {
 			profile_image_url,
 			address,
 			type,
-			created_at
+			created_at,
+			banned_at,
+			email_verified_at
 		FROM users
 		WHERE id = $1
 	`
 
 	var result repo.User
 
-	err := ur.db.Get(&result, query, id)
+	err := ur.db.GetContext(ctx, &result, query, id)
 
 	if err != nil {
 		return nil, err
@@ -91,7 +103,10 @@ func (ur *userRepo) Get(id int64) (*repo.User, error) {
 	return &result, nil
 }
 
-func (ur *userRepo) GetByEmail(email string) (*repo.User, error) {
+func (ur *userRepo) GetByEmail(ctx context.Context, email string) (*repo.User, error) {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	query := `
 		SELECT
 			id,
@@ -105,14 +120,18 @@ func (ur *userRepo) GetByEmail(email string) (*repo.User, error) {
 			profile_image_url,
 			address,
 			type,
-			created_at
+			created_at,
+			banned_at,
+			email_verified_at
 		FROM users
-		WHERE email = $1
+		WHERE lower(email) = lower($1)
+		ORDER BY email = $1 DESC, id
+		LIMIT 1
 	`
 
 	var result repo.User
 
-	err := ur.db.Get(&result, query, email)
+	err := ur.db.GetContext(ctx, &result, query, email)
 
 	if err != nil {
 		return nil, err
@@ -121,28 +140,21 @@ func (ur *userRepo) GetByEmail(email string) (*repo.User, error) {
 	return &result, nil
 }
 
-func (ur *userRepo) GetAll(params *repo.GetUsersParams) (*repo.GetUsersResult, error) {
+func (ur *userRepo) GetAll(ctx context.Context, params *repo.GetUsersParams) (*repo.GetUsersResult, error) {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	result := repo.GetUsersResult{
 		Users: make([]*repo.User, 0),
 		Count: 0,
 	}
 
-	offset := (params.Page - 1) * params.Limit
-
-	limit := fmt.Sprintf(" LIMIT %d OFFSET %d ", params.Limit, offset)
-
-	filter := ""
+	q := newListQuery().
+		Search(params.Search, "first_name", "last_name", "phone_number", "email").
+		OrderBy("created_at", "desc").
+		Paginate(int64(params.Page), int64(params.Limit))
 
-	if params.Search != "" {
-		str := "%" + params.Search + "%"
-		filter += fmt.Sprintf(`
-				WHERE first_name ILIKE '%s' OR last_name ILIKE '%s' OR phone_number ILIKE '%s' 
-				OR email ILIKE '%s'`,
-			str, str, str, str,
-		)
-	}
-
-	query := `
+	query, args := q.Select(`
 		SELECT
 			id,
 			first_name,
@@ -155,21 +167,21 @@ func (ur *userRepo) GetAll(params *repo.GetUsersParams) (*repo.GetUsersResult, e
 			profile_image_url,
 			address,
 			type,
-			created_at
+			created_at,
+			banned_at,
+			email_verified_at
 		FROM users
-		` + filter + `
-		ORDER BY created_at DESC
-		` + limit
+	`)
 
-	err := ur.db.Select(&result.Users, query)
+	err := ur.db.SelectContext(ctx, &result.Users, query, args...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	queryCount := `SELECT count(1) FROM users ` + filter
+	queryCount, args := q.Count(`SELECT count(1) FROM users `)
 
-	err = ur.db.Get(&result.Count, queryCount)
+	err = ur.db.GetContext(ctx, &result.Count, queryCount, args...)
 
 	if err != nil {
 		return nil, err
@@ -178,7 +190,10 @@ func (ur *userRepo) GetAll(params *repo.GetUsersParams) (*repo.GetUsersResult, e
 	return &result, nil
 }
 
-func (ur *userRepo) Update(user *repo.User) error {
+func (ur *userRepo) Update(ctx context.Context, user *repo.User) error {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	query := `
 		UPDATE users SET
 			first_name = $1,
@@ -190,11 +205,12 @@ func (ur *userRepo) Update(user *repo.User) error {
 			password = $7,
 			profile_image_url = $8,
 			address = $9,
-			type = $10
+			type = $10,
+			email_verified_at = CASE WHEN email = $4 THEN email_verified_at END
 		WHERE id = $11
 	`
 
-	result, err := ur.db.Exec(
+	result, err := ur.db.ExecContext(ctx,
 		query,
 		user.FirstName,
 		user.LastName,
@@ -226,10 +242,13 @@ func (ur *userRepo) Update(user *repo.User) error {
 	return nil
 }
 
-func (ur *userRepo) Delete(id int64) error {
+func (ur *userRepo) Delete(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	query := `DELETE FROM users WHERE id = $1`
 
-	resutl, err := ur.db.Exec(query, id)
+	resutl, err := ur.db.ExecContext(ctx, query, id)
 
 	if err != nil {
 		return err
@@ -248,10 +267,13 @@ func (ur *userRepo) Delete(id int64) error {
 	return nil
 }
 
-func (ur *userRepo) UpdatePassword(req *repo.UpdatePassword) error {
+func (ur *userRepo) UpdatePassword(ctx context.Context, req *repo.UpdatePassword) error {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
 	query := `UPDATE users SET password = $1 WHERE id = $2`
 
-	_, err := ur.db.Exec(
+	_, err := ur.db.ExecContext(ctx,
 		query,
 		req.Password,
 		req.UserID,
@@ -263,3 +285,53 @@ func (ur *userRepo) UpdatePassword(req *repo.UpdatePassword) error {
 
 	return nil
 }
+
+func (ur *userRepo) Ban(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
+	query := `UPDATE users SET banned_at = CURRENT_TIMESTAMP WHERE id = $1 AND banned_at IS NULL`
+
+	result, err := ur.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (ur *userRepo) Unban(ctx context.Context, id int64) error {
+	ctx, cancel := withTimeout(ctx, ur.timeout)
+	defer cancel()
+
+	query := `UPDATE users SET banned_at = NULL WHERE id = $1 AND banned_at IS NOT NULL`
+
+	result, err := ur.db.ExecContext(ctx, query, id)
+
+	if err != nil {
+		return err
+	}
+
+	rowsCount, err := result.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if rowsCount == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
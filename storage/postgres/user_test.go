@@ -1,7 +1,9 @@
 package postgres_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/bxcodec/faker/v4"
 	"github.com/ibrat-muslim/booking-service/storage/repo"
@@ -9,7 +11,7 @@ import (
 )
 
 func createUser(t *testing.T) *repo.User {
-	user, err := strg.User().Create(&repo.User{
+	user, err := strg.User().Create(ctx, &repo.User{
 		FirstName: faker.FirstName(),
 		LastName:  faker.LastName(),
 		DateOfBirth: faker.Date(),
@@ -25,7 +27,7 @@ func createUser(t *testing.T) *repo.User {
 }
 
 func deleteUser(id int64, t *testing.T) {
-	err := strg.User().Delete(id)
+	err := strg.User().Delete(ctx, id)
 	require.NoError(t, err)
 }
 
@@ -37,7 +39,7 @@ func TestCreateUser(t *testing.T) {
 func TestGetUser(t *testing.T) {
 	u := createUser(t)
 
-	user, err := strg.User().Get(u.ID)
+	user, err := strg.User().Get(ctx, u.ID)
 	require.NoError(t, err)
 	require.NotEmpty(t, user)
 
@@ -47,7 +49,7 @@ func TestGetUser(t *testing.T) {
 func TestGetAllUsers(t *testing.T) {
 	u := createUser(t)
 
-	users, err := strg.User().GetAll(&repo.GetUsersParams{
+	users, err := strg.User().GetAll(ctx, &repo.GetUsersParams{
 		Limit:  10,
 		Page:   1,
 		Search: u.FirstName,
@@ -60,6 +62,22 @@ func TestGetAllUsers(t *testing.T) {
 	deleteUser(u.ID, t)
 }
 
+func TestGetAllUsersHostileSearch(t *testing.T) {
+	u := createUser(t)
+
+	users, err := strg.User().GetAll(ctx, &repo.GetUsersParams{
+		Limit:  10,
+		Page:   1,
+		Search: `%' OR '1'='1`,
+	})
+
+	require.NoError(t, err)
+	require.Empty(t, users.Users)
+	require.Zero(t, users.Count)
+
+	deleteUser(u.ID, t)
+}
+
 func TestUpdateUser(t *testing.T) {
 	u := createUser(t)
 
@@ -70,8 +88,49 @@ func TestUpdateUser(t *testing.T) {
 	u.Password = faker.Password()
 	u.Type = repo.UserTypeGuest
 
-	err := strg.User().Update(u)
+	err := strg.User().Update(ctx, u)
+	require.NoError(t, err)
+
+	deleteUser(u.ID, t)
+}
+
+func TestUpdateUserEmailVerification(t *testing.T) {
+	verifiedAt := time.Now()
+
+	u, err := strg.User().Create(ctx, &repo.User{
+		FirstName:       faker.FirstName(),
+		LastName:        faker.LastName(),
+		DateOfBirth:     faker.Date(),
+		Email:           faker.Email(),
+		Password:        faker.Password(),
+		Type:            repo.UserTypeGuest,
+		EmailVerifiedAt: &verifiedAt,
+	})
+	require.NoError(t, err)
+
+	user, err := strg.User().GetByEmail(ctx, strings.ToUpper(u.Email))
+	require.NoError(t, err)
+	require.Equal(t, u.ID, user.ID)
+	require.NotNil(t, user.EmailVerifiedAt)
+
+	// Other changes keep the email verified
+	u.FirstName = faker.FirstName()
+
+	err = strg.User().Update(ctx, u)
+	require.NoError(t, err)
+
+	user, err = strg.User().Get(ctx, u.ID)
 	require.NoError(t, err)
+	require.NotNil(t, user.EmailVerifiedAt)
+
+	u.Email = faker.Email()
+
+	err = strg.User().Update(ctx, u)
+	require.NoError(t, err)
+
+	user, err = strg.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Nil(t, user.EmailVerifiedAt)
 
 	deleteUser(u.ID, t)
 }
@@ -80,3 +139,23 @@ func TestDeleteUser(t *testing.T) {
 	u := createUser(t)
 	deleteUser(u.ID, t)
 }
+
+func TestBanUser(t *testing.T) {
+	u := createUser(t)
+
+	err := strg.User().Ban(ctx, u.ID)
+	require.NoError(t, err)
+
+	user, err := strg.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.NotNil(t, user.BannedAt)
+
+	err = strg.User().Unban(ctx, u.ID)
+	require.NoError(t, err)
+
+	user, err = strg.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Nil(t, user.BannedAt)
+
+	deleteUser(u.ID, t)
+}
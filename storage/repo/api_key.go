@@ -0,0 +1,33 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// APIKey lets integrations act as its user, limited to its scopes. Only the
+// hash of the key is stored; the prefix identifies it.
+type APIKey struct {
+	ID         int64      `db:"id"`
+	UserID     int64      `db:"user_id"`
+	Name       string     `db:"name"`
+	Prefix     string     `db:"prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     []string   `db:"scopes"`
+	ExpiresAt  *time.Time `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+type APIKeyStorageI interface {
+	Create(ctx context.Context, key *APIKey) (*APIKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+	GetAll(ctx context.Context, userID int64) ([]*APIKey, error)
+	// Touch sets the last used time of the key to now
+	Touch(ctx context.Context, id int64) error
+	// Delete deletes the key of the user. It returns sql.ErrNoRows if the
+	// user has no such key.
+	Delete(ctx context.Context, id, userID int64) error
+	// DeleteAll deletes every key of the user
+	DeleteAll(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	BookingStatusPending   = "pending"
+	BookingStatusConfirmed = "confirmed"
+	BookingStatusCheckedIn = "checked_in"
+	BookingStatusCompleted = "completed"
+	BookingStatusCancelled = "cancelled"
+	BookingStatusNoShow    = "no_show"
+)
+
+var (
+	ErrRoomNotAvailable        = errors.New("room is not available for the selected dates")
+	ErrInvalidStatusTransition = errors.New("invalid booking status transition")
+)
+
+// ActiveBookingStatuses are the statuses in which a booking holds its room
+var ActiveBookingStatuses = []string{
+	BookingStatusPending,
+	BookingStatusConfirmed,
+	BookingStatusCheckedIn,
+	BookingStatusCompleted,
+}
+
+// BookingTransitions lists the statuses a booking may move to from each status.
+// Completed, cancelled and no_show bookings are final.
+var BookingTransitions = map[string][]string{
+	BookingStatusPending:   {BookingStatusConfirmed, BookingStatusCancelled},
+	BookingStatusConfirmed: {BookingStatusCheckedIn, BookingStatusCancelled, BookingStatusNoShow},
+	BookingStatusCheckedIn: {BookingStatusCompleted},
+}
+
+// CanTransitionBooking reports whether a booking in status from may move to status to
+func CanTransitionBooking(from, to string) bool {
+	for _, s := range BookingTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+type Booking struct {
+	ID           int64      `db:"id"`
+	RoomID       int64      `db:"room_id"`
+	GuestID      int64      `db:"guest_id"`
+	CheckIn      time.Time  `db:"check_in"`
+	CheckOut     time.Time  `db:"check_out"`
+	GuestsCount  int32      `db:"guests_count"`
+	TotalPrice   float64    `db:"total_price"`
+	Status       string     `db:"status"`
+	RefundAmount *float64   `db:"refund_amount"`
+	CancelledAt  *time.Time `db:"cancelled_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+	UpdatedAt    *time.Time `db:"updated_at"`
+}
+
+type GetBookingsParams struct {
+	Limit      int32  `db:"limit"`
+	Page       int32  `db:"page"`
+	GuestID    int64  `db:"guest_id"`
+	RoomID     int64  `db:"room_id"`
+	PropertyID int64  `db:"property_id"`
+	OwnerID    int64  `db:"owner_id"`
+	Status     string `db:"status"`
+}
+
+type GetBookingsResult struct {
+	Bookings []*Booking `db:"bookings"`
+	Count    int32      `db:"count"`
+}
+
+type GetRoomStaysParams struct {
+	RoomID   int64     `db:"room_id"`
+	From     time.Time `db:"from"`
+	Statuses []string  `db:"statuses"`
+}
+
+type UpdateBookingStatus struct {
+	ID     int64  `db:"id"`
+	Status string `db:"status"`
+}
+
+type CancelBooking struct {
+	ID           int64     `db:"id"`
+	RefundAmount float64   `db:"refund_amount"`
+	CancelledAt  time.Time `db:"cancelled_at"`
+}
+
+type BookingStorageI interface {
+	Create(ctx context.Context, booking *Booking) (*Booking, error)
+	Get(ctx context.Context, id int64) (*Booking, error)
+	GetAll(ctx context.Context, params *GetBookingsParams) (*GetBookingsResult, error)
+	// GetRoomStays returns the bookings of the room in one of the statuses checking out after From
+	GetRoomStays(ctx context.Context, params *GetRoomStaysParams) ([]*Booking, error)
+	UpdateStatus(ctx context.Context, req *UpdateBookingStatus) error
+	// Cancel cancels the booking and records the refund it is owed. The refund
+	// is 0 unless a payment of the booking was captured. It returns
+	// ErrPaymentInProgress while a payment of the booking is not captured yet.
+	Cancel(ctx context.Context, req *CancelBooking) error
+}
@@ -1,6 +1,9 @@
 package repo
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Category struct {
 	ID        int64     `db:"id"`
@@ -20,9 +23,9 @@ type GetCategoriesResult struct {
 }
 
 type CategoryStorageI interface {
-	Create(category *Category) (*Category, error)
-	Get(id int64) (*Category, error)
-	GetAll(params *GetCategoriesParams) (*GetCategoriesResult, error)
-	Update(category *Category) error
-	Delete(id int64) error
+	Create(ctx context.Context, category *Category) (*Category, error)
+	Get(ctx context.Context, id int64) (*Category, error)
+	GetAll(ctx context.Context, params *GetCategoriesParams) (*GetCategoriesResult, error)
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, id int64) error
 }
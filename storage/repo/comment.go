@@ -1,6 +1,9 @@
 package repo
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Comment struct {
 	ID          int64      `db:"id"`
@@ -30,8 +33,10 @@ type GetCommentsResult struct {
 }
 
 type CommentStorageI interface {
-	Create(comment *Comment) (*Comment, error)
-	GetAll(params *GetCommentsParams) (*GetCommentsResult, error)
-	Update(comment *Comment) error
-	Delete(id int64) error
+	Create(ctx context.Context, comment *Comment) (*Comment, error)
+	GetAll(ctx context.Context, params *GetCommentsParams) (*GetCommentsResult, error)
+	// GetAuthorID returns the ID of the user who wrote the comment
+	GetAuthorID(ctx context.Context, id int64) (int64, error)
+	Update(ctx context.Context, comment *Comment) error
+	Delete(ctx context.Context, id int64) error
 }
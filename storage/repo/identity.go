@@ -0,0 +1,22 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// Identity links a user to the subject of an external OpenID Connect
+// provider the user signs in with
+type Identity struct {
+	ID        int64     `db:"id"`
+	Provider  string    `db:"provider"`
+	Subject   string    `db:"subject"`
+	UserID    int64     `db:"user_id"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type IdentityStorageI interface {
+	Create(ctx context.Context, identity *Identity) (*Identity, error)
+	Get(ctx context.Context, provider, subject string) (*Identity, error)
+}
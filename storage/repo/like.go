@@ -1,5 +1,7 @@
 package repo
 
+import "context"
+
 type Like struct {
 	ID     int64 `db:"id"`
 	PostID int64 `db:"post_id"`
@@ -13,7 +15,7 @@ type LikesDislikesCountsResult struct {
 }
 
 type LikeStorageI interface {
-	CreateOrUpdate(like *Like) error
-	Get(postID, userID int64) (*Like, error)
-	GetLikesDislikesCount(postID int64) (*LikesDislikesCountsResult, error)
+	CreateOrUpdate(ctx context.Context, like *Like) error
+	Get(ctx context.Context, postID, userID int64) (*Like, error)
+	GetLikesDislikesCount(ctx context.Context, postID int64) (*LikesDislikesCountsResult, error)
 }
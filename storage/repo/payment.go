@@ -0,0 +1,72 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// PaymentStatusPending claims the booking while the provider is called
+	PaymentStatusPending    = "pending"
+	PaymentStatusAuthorized = "authorized"
+	PaymentStatusCaptured   = "captured"
+	PaymentStatusRefunded   = "refunded"
+	PaymentStatusFailed     = "failed"
+)
+
+const (
+	PaymentEventCaptured = "payment.captured"
+	PaymentEventRefunded = "payment.refunded"
+	PaymentEventFailed   = "payment.failed"
+)
+
+var (
+	ErrBookingNotPending = errors.New("only pending bookings can be paid")
+	ErrPaymentInProgress = errors.New("a payment of the booking is already in progress")
+)
+
+// ActivePaymentStatuses are the statuses of the payment a booking may have
+// at most one of
+var ActivePaymentStatuses = []string{
+	PaymentStatusPending,
+	PaymentStatusAuthorized,
+	PaymentStatusCaptured,
+}
+
+type Payment struct {
+	ID                int64      `db:"id"`
+	BookingID         int64      `db:"booking_id"`
+	ProviderPaymentID string     `db:"provider_payment_id"`
+	Amount            float64    `db:"amount"`
+	RefundedAmount    float64    `db:"refunded_amount"`
+	Status            string     `db:"status"`
+	CreatedAt         time.Time  `db:"created_at"`
+	UpdatedAt         *time.Time `db:"updated_at"`
+}
+
+// PaymentEvent is a change of a payment reported by the provider. Amount is
+// the total refunded so far for refund events.
+type PaymentEvent struct {
+	ID                string  `db:"id"`
+	Type              string  `db:"type"`
+	ProviderPaymentID string  `db:"provider_payment_id"`
+	Amount            float64 `db:"amount"`
+}
+
+type PaymentStorageI interface {
+	// Create claims the booking for the payment. It returns ErrBookingNotPending
+	// if the booking is not pending and ErrPaymentInProgress if the booking
+	// already has an active payment.
+	Create(ctx context.Context, payment *Payment) (*Payment, error)
+	// Authorize records the provider payment of a pending payment
+	Authorize(ctx context.Context, id int64, providerPaymentID string) error
+	// Fail releases the booking of a payment which was not captured
+	Fail(ctx context.Context, id int64) error
+	Get(ctx context.Context, id int64) (*Payment, error)
+	// GetByBooking returns the latest payment of the booking
+	GetByBooking(ctx context.Context, bookingID int64) (*Payment, error)
+	// ApplyEvent updates the payment, and confirms its booking once captured.
+	// It reports false without changing anything if the event was already applied.
+	ApplyEvent(ctx context.Context, event *PaymentEvent) (bool, error)
+}
@@ -1,6 +1,9 @@
 package repo
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Post struct {
 	ID          int64      `db:"id"`
@@ -33,9 +36,11 @@ type GetPostsResult struct {
 }
 
 type PostStorageI interface {
-	Create(post *Post) (*Post, error)
-	Get(id int64) (*Post, error)
-	GetAll(params *GetPostsParams) (*GetPostsResult, error)
-	Update(post *Post) error
-	Delete(id int64) error
+	Create(ctx context.Context, post *Post) (*Post, error)
+	Get(ctx context.Context, id int64) (*Post, error)
+	// GetAuthorID returns the ID of the user who wrote the post
+	GetAuthorID(ctx context.Context, id int64) (int64, error)
+	GetAll(ctx context.Context, params *GetPostsParams) (*GetPostsResult, error)
+	Update(ctx context.Context, post *Post) error
+	Delete(ctx context.Context, id int64) error
 }
@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	PricingRuleTypeSeason       = "season"
+	PricingRuleTypeWeekday      = "weekday"
+	PricingRuleTypeMinNights    = "min_nights"
+	PricingRuleTypeLengthOfStay = "length_of_stay"
+)
+
+type PricingRule struct {
+	ID              int64      `db:"id"`
+	RoomID          int64      `db:"room_id"`
+	Type            string     `db:"type"`
+	StartDate       *time.Time `db:"start_date"`
+	EndDate         *time.Time `db:"end_date"`
+	DaysOfWeek      []int64    `db:"days_of_week"`
+	Price           *float64   `db:"price"`
+	Multiplier      *float64   `db:"multiplier"`
+	MinNights       *int32     `db:"min_nights"`
+	DiscountPercent *float64   `db:"discount_percent"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+type PricingRuleStorageI interface {
+	Create(ctx context.Context, rule *PricingRule) (*PricingRule, error)
+	Get(ctx context.Context, id int64) (*PricingRule, error)
+	GetAll(ctx context.Context, roomID int64) ([]*PricingRule, error)
+	// GetByRooms returns the rules of all the rooms, ordered by room
+	GetByRooms(ctx context.Context, roomIDs []int64) ([]*PricingRule, error)
+	Delete(ctx context.Context, id int64) error
+}
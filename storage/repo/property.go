@@ -0,0 +1,67 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	CancellationPolicyFlexible = "flexible"
+	CancellationPolicyModerate = "moderate"
+	CancellationPolicyStrict   = "strict"
+	CancellationPolicyCustom   = "custom"
+)
+
+type Property struct {
+	ID                 int64               `db:"id"`
+	OwnerID            int64               `db:"owner_id"`
+	Title              string              `db:"title"`
+	Description        string              `db:"description"`
+	Address            string              `db:"address"`
+	City               string              `db:"city"`
+	Latitude           float64             `db:"latitude"`
+	Longitude          float64             `db:"longitude"`
+	Amenities          []string            `db:"amenities"`
+	CancellationPolicy string              `db:"cancellation_policy"`
+	CancellationTiers  []*CancellationTier `db:"cancellation_tiers"`
+	CreatedAt          time.Time           `db:"created_at"`
+	UpdatedAt          *time.Time          `db:"updated_at"`
+}
+
+// CancellationTier refunds RefundPercent of the booking total when the booking
+// is cancelled at least DaysBefore days before check-in
+type CancellationTier struct {
+	DaysBefore    int32   `json:"days_before"`
+	RefundPercent float64 `json:"refund_percent"`
+}
+
+type GetPropertiesParams struct {
+	Limit   int32  `db:"limit"`
+	Page    int32  `db:"page"`
+	Search  string `db:"search"`
+	City    string `db:"city"`
+	OwnerID int64  `db:"owner_id"`
+}
+
+type GetAvailablePropertiesParams struct {
+	Limit    int32     `db:"limit"`
+	Page     int32     `db:"page"`
+	City     string    `db:"city"`
+	CheckIn  time.Time `db:"check_in"`
+	CheckOut time.Time `db:"check_out"`
+	Guests   int32     `db:"guests"`
+}
+
+type GetPropertiesResult struct {
+	Properties []*Property `db:"properties"`
+	Count      int32       `db:"count"`
+}
+
+type PropertyStorageI interface {
+	Create(ctx context.Context, property *Property) (*Property, error)
+	Get(ctx context.Context, id int64) (*Property, error)
+	GetAll(ctx context.Context, params *GetPropertiesParams) (*GetPropertiesResult, error)
+	GetAvailable(ctx context.Context, params *GetAvailablePropertiesParams) (*GetPropertiesResult, error)
+	Update(ctx context.Context, property *Property) error
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,43 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// RevenueBookingStatuses are the statuses in which a booking counts towards
+// occupancy and revenue
+var RevenueBookingStatuses = []string{
+	BookingStatusConfirmed,
+	BookingStatusCheckedIn,
+	BookingStatusCompleted,
+}
+
+type GetReportParams struct {
+	OwnerID    int64     `db:"owner_id"`
+	PropertyID int64     `db:"property_id"`
+	From       time.Time `db:"from"`
+	// To is exclusive
+	To time.Time `db:"to"`
+}
+
+// PropertyMonthReport holds the figures of a property for the part of a
+// month that falls within the requested period
+type PropertyMonthReport struct {
+	PropertyID      int64     `db:"property_id"`
+	PropertyTitle   string    `db:"property_title"`
+	Month           time.Time `db:"month"`
+	AvailableNights int64     `db:"available_nights"`
+	BookedNights    int64     `db:"booked_nights"`
+	OccupancyRate   float64   `db:"occupancy_rate"`
+	Revenue         float64   `db:"revenue"`
+	ADR             float64   `db:"adr"`
+	RevPAR          float64   `db:"revpar"`
+	Bookings        int64     `db:"bookings"`
+	Cancellations   int64     `db:"cancellations"`
+	AverageLeadTime float64   `db:"average_lead_time"`
+}
+
+type ReportStorageI interface {
+	GetPropertyMonthly(ctx context.Context, params *GetReportParams) ([]*PropertyMonthReport, error)
+}
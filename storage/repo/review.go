@@ -0,0 +1,59 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrReviewExists = errors.New("booking has already been reviewed")
+	ErrReplyExists  = errors.New("review has already been replied")
+)
+
+type Review struct {
+	ID         int64      `db:"id"`
+	BookingID  int64      `db:"booking_id"`
+	PropertyID int64      `db:"property_id"`
+	GuestID    int64      `db:"guest_id"`
+	Rating     int32      `db:"rating"`
+	Text       string     `db:"text"`
+	Reply      *string    `db:"reply"`
+	RepliedAt  *time.Time `db:"replied_at"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+type GetReviewsParams struct {
+	Limit      int32 `db:"limit"`
+	Page       int32 `db:"page"`
+	PropertyID int64 `db:"property_id"`
+}
+
+type GetReviewsResult struct {
+	Reviews []*Review `db:"reviews"`
+	Count   int32     `db:"count"`
+}
+
+type ReplyReview struct {
+	ID        int64     `db:"id"`
+	Reply     string    `db:"reply"`
+	RepliedAt time.Time `db:"replied_at"`
+}
+
+type RatingSummaryResult struct {
+	Average    float64 `db:"average"`
+	Count      int64   `db:"count"`
+	OneStar    int64   `db:"one_star"`
+	TwoStars   int64   `db:"two_stars"`
+	ThreeStars int64   `db:"three_stars"`
+	FourStars  int64   `db:"four_stars"`
+	FiveStars  int64   `db:"five_stars"`
+}
+
+type ReviewStorageI interface {
+	Create(ctx context.Context, review *Review) (*Review, error)
+	Get(ctx context.Context, id int64) (*Review, error)
+	GetAll(ctx context.Context, params *GetReviewsParams) (*GetReviewsResult, error)
+	Reply(ctx context.Context, req *ReplyReview) error
+	GetRatingSummary(ctx context.Context, propertyID int64) (*RatingSummaryResult, error)
+}
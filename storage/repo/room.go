@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+type Room struct {
+	ID          int64      `db:"id"`
+	PropertyID  int64      `db:"property_id"`
+	Title       string     `db:"title"`
+	Description string     `db:"description"`
+	Capacity    int32      `db:"capacity"`
+	BasePrice   float64    `db:"base_price"`
+	Photos      []string   `db:"photos"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   *time.Time `db:"updated_at"`
+}
+
+type GetRoomsParams struct {
+	Limit      int32 `db:"limit"`
+	Page       int32 `db:"page"`
+	PropertyID int64 `db:"property_id"`
+}
+
+type GetAvailableRoomsParams struct {
+	PropertyIDs []int64   `db:"property_ids"`
+	CheckIn     time.Time `db:"check_in"`
+	CheckOut    time.Time `db:"check_out"`
+	Guests      int32     `db:"guests"`
+}
+
+type RoomCalendarDay struct {
+	Date      time.Time `db:"date"`
+	Available bool      `db:"available"`
+	Price     float64   `db:"price"`
+}
+
+type GetRoomCalendarParams struct {
+	RoomID int64     `db:"room_id"`
+	From   time.Time `db:"from"`
+	To     time.Time `db:"to"`
+}
+
+type GetRoomsResult struct {
+	Rooms []*Room `db:"rooms"`
+	Count int32   `db:"count"`
+}
+
+type RoomStorageI interface {
+	Create(ctx context.Context, room *Room) (*Room, error)
+	Get(ctx context.Context, id int64) (*Room, error)
+	GetAll(ctx context.Context, params *GetRoomsParams) (*GetRoomsResult, error)
+	// GetAvailable returns the free rooms of all the properties, ordered by
+	// property and price
+	GetAvailable(ctx context.Context, params *GetAvailableRoomsParams) ([]*Room, error)
+	GetCalendar(ctx context.Context, params *GetRoomCalendarParams) ([]*RoomCalendarDay, error)
+	Update(ctx context.Context, room *Room) error
+	Delete(ctx context.Context, id int64) error
+	SetCalendarToken(ctx context.Context, id int64, token string) error
+	// GetCalendarToken returns nil if no calendar feed has been set up for the room
+	GetCalendarToken(ctx context.Context, id int64) (*string, error)
+}
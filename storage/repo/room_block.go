@@ -0,0 +1,53 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	RoomBlockSourceManual = "manual"
+	RoomBlockSourceICal   = "ical"
+)
+
+// RoomBlock makes a room unavailable from StartDate until EndDate, exclusive
+type RoomBlock struct {
+	ID        int64      `db:"id"`
+	RoomID    int64      `db:"room_id"`
+	StartDate time.Time  `db:"start_date"`
+	EndDate   time.Time  `db:"end_date"`
+	Reason    string     `db:"reason"`
+	Source    string     `db:"source"`
+	UID       *string    `db:"uid"`
+	CreatedAt time.Time  `db:"created_at"`
+	UpdatedAt *time.Time `db:"updated_at"`
+}
+
+type GetRoomBlocksParams struct {
+	RoomID int64 `db:"room_id"`
+	// From skips blocks ending on or before it, unless zero
+	From time.Time `db:"from"`
+}
+
+// ImportRoomBlocks creates or updates Blocks of the room matching them by UID
+// and removes the imported blocks with CancelledUIDs
+type ImportRoomBlocks struct {
+	RoomID        int64        `db:"room_id"`
+	Blocks        []*RoomBlock `db:"blocks"`
+	CancelledUIDs []string     `db:"cancelled_uids"`
+}
+
+type ImportRoomBlocksResult struct {
+	Created   int32 `db:"created"`
+	Updated   int32 `db:"updated"`
+	Unchanged int32 `db:"unchanged"`
+	Removed   int32 `db:"removed"`
+}
+
+type RoomBlockStorageI interface {
+	Create(ctx context.Context, block *RoomBlock) (*RoomBlock, error)
+	Get(ctx context.Context, id int64) (*RoomBlock, error)
+	GetAll(ctx context.Context, params *GetRoomBlocksParams) ([]*RoomBlock, error)
+	Import(ctx context.Context, req *ImportRoomBlocks) (*ImportRoomBlocksResult, error)
+	Delete(ctx context.Context, id int64) error
+}
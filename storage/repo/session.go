@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+	"time"
+)
+
+// Session is a login of a user on a device. It holds the hash of the current
+// refresh token of the session; earlier tokens are rotated out on refresh.
+type Session struct {
+	ID               string     `db:"id"`
+	UserID           int64      `db:"user_id"`
+	RefreshTokenHash string     `db:"refresh_token_hash"`
+	UserAgent        string     `db:"user_agent"`
+	IPAddress        string     `db:"ip_address"`
+	CreatedAt        time.Time  `db:"created_at"`
+	RefreshedAt      *time.Time `db:"refreshed_at"`
+	ExpiresAt        time.Time  `db:"expires_at"`
+	RevokedAt        *time.Time `db:"revoked_at"`
+}
+
+type RotateSession struct {
+	ID      string `db:"id"`
+	OldHash string `db:"old_hash"`
+	NewHash string `db:"new_hash"`
+}
+
+type SessionStorageI interface {
+	Create(ctx context.Context, session *Session) (*Session, error)
+	Get(ctx context.Context, id string) (*Session, error)
+	// GetActive returns the sessions of the user which are neither revoked nor expired
+	GetActive(ctx context.Context, userID int64) ([]*Session, error)
+	// Rotate replaces the refresh token of an active session. It returns
+	// sql.ErrNoRows if OldHash is not the current token of the session.
+	Rotate(ctx context.Context, req *RotateSession) error
+	Revoke(ctx context.Context, id string) error
+	// RevokeAll revokes the active sessions of the user except keepID and
+	// returns their IDs
+	RevokeAll(ctx context.Context, userID int64, keepID string) ([]string, error)
+}
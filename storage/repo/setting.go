@@ -0,0 +1,16 @@
+package repo
+
+import "context"
+
+// Keys of the settings
+const (
+	// SettingTwoFactorRequired is "true" when superadmins and owners must
+	// use two-factor authentication
+	SettingTwoFactorRequired = "two_factor_required"
+)
+
+type SettingStorageI interface {
+	// Get returns sql.ErrNoRows if the setting was never set
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key, value string) error
+}
@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+
+// TwoFactor is the TOTP secret of a user. It takes effect once enabled by
+// confirming a code from the authenticator app.
+type TwoFactor struct {
+	UserID int64  `db:"user_id"`
+	Secret string `db:"secret"`
+	// LastUsedStep is the TOTP step of the last accepted code
+	LastUsedStep int64      `db:"last_used_step"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	CreatedAt    time.Time  `db:"created_at"`
+}
+
+// EnrollTwoFactor replaces the pending secret and the recovery codes of the user
+type EnrollTwoFactor struct {
+	UserID             int64    `db:"user_id"`
+	Secret             string   `db:"secret"`
+	RecoveryCodeHashes []string `db:"recovery_code_hashes"`
+}
+
+type TwoFactorStorageI interface {
+	// Enroll returns ErrTwoFactorEnabled if the user already enabled it
+	Enroll(ctx context.Context, req *EnrollTwoFactor) error
+	Get(ctx context.Context, userID int64) (*TwoFactor, error)
+	// Enable returns sql.ErrNoRows if the user has no pending secret
+	Enable(ctx context.Context, userID, step int64) error
+	// UseStep records the step of an accepted code. It returns sql.ErrNoRows
+	// if a code of the same or a later step was already used.
+	UseStep(ctx context.Context, userID, step int64) error
+	// UseRecoveryCode returns sql.ErrNoRows if the code is unknown or used
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash string) error
+	Disable(ctx context.Context, userID int64) error
+}
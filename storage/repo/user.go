@@ -1,6 +1,9 @@
 package repo
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 const (
 	UserTypeSuperAdmin = "superadmin"
@@ -9,18 +12,22 @@ const (
 )
 
 type User struct {
-	ID              int64     `db:"id"`
-	FirstName       string    `db:"first_name"`
-	LastName        string    `db:"last_name"`
-	DateOfBirth     string    `db:"dob"`
-	Email           string    `db:"email"`
-	PhoneNumber     *string   `db:"phone_number"`
-	Gender          string    `db:"gender"`
-	Password        string    `db:"password"`
-	ProfileImageUrl *string   `db:"profile_image_url"`
-	Address         *string   `db:"address"`
-	Type            string    `db:"type"`
-	CreatedAt       time.Time `db:"created_at"`
+	ID              int64      `db:"id"`
+	FirstName       string     `db:"first_name"`
+	LastName        string     `db:"last_name"`
+	DateOfBirth     string     `db:"dob"`
+	Email           string     `db:"email"`
+	PhoneNumber     *string    `db:"phone_number"`
+	Gender          string     `db:"gender"`
+	Password        string     `db:"password"`
+	ProfileImageUrl *string    `db:"profile_image_url"`
+	Address         *string    `db:"address"`
+	Type            string     `db:"type"`
+	CreatedAt       time.Time  `db:"created_at"`
+	BannedAt        *time.Time `db:"banned_at"`
+	// EmailVerifiedAt is nil until the user proves to own the email, and is
+	// cleared when the email changes
+	EmailVerifiedAt *time.Time `db:"email_verified_at"`
 }
 
 type GetUsersParams struct {
@@ -40,11 +47,14 @@ type UpdatePassword struct {
 }
 
 type UserStorageI interface {
-	Create(user *User) (*User, error)
-	Get(id int64) (*User, error)
-	GetByEmail(email string) (*User, error)
-	GetAll(params *GetUsersParams) (*GetUsersResult, error)
-	Update(user *User) error
-	Delete(id int64) error
-	UpdatePassword(req *UpdatePassword) error
+	Create(ctx context.Context, user *User) (*User, error)
+	Get(ctx context.Context, id int64) (*User, error)
+	// GetByEmail matches the email case-insensitively, preferring an exact match
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	GetAll(ctx context.Context, params *GetUsersParams) (*GetUsersResult, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id int64) error
+	UpdatePassword(ctx context.Context, req *UpdatePassword) error
+	Ban(ctx context.Context, id int64) error
+	Unban(ctx context.Context, id int64) error
 }
@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"context"
+	"time"
+
 	"github.com/ibrat-muslim/booking-service/storage/postgres"
 	"github.com/ibrat-muslim/booking-service/storage/repo"
 	"github.com/jmoiron/sqlx"
@@ -12,23 +15,77 @@ type StorageI interface {
 	Post() repo.PostStorageI
 	Comment() repo.CommentStorageI
 	Like() repo.LikeStorageI
+	Property() repo.PropertyStorageI
+	Room() repo.RoomStorageI
+	Booking() repo.BookingStorageI
+	PricingRule() repo.PricingRuleStorageI
+	Payment() repo.PaymentStorageI
+	Review() repo.ReviewStorageI
+	Report() repo.ReportStorageI
+	RoomBlock() repo.RoomBlockStorageI
+	Session() repo.SessionStorageI
+	TwoFactor() repo.TwoFactorStorageI
+	Setting() repo.SettingStorageI
+	Identity() repo.IdentityStorageI
+	APIKey() repo.APIKeyStorageI
+	// WithTx runs fn with repositories bound to a single transaction, which
+	// is committed if fn returns nil and rolled back otherwise. fn is run
+	// again when the transaction fails to serialize with concurrent ones, so
+	// it must not have other side effects.
+	WithTx(ctx context.Context, fn func(StorageI) error) error
 }
 
 type storagePg struct {
-	userRepo     repo.UserStorageI
-	categoryRepo repo.CategoryStorageI
-	postRepo     repo.PostStorageI
-	commentRepo  repo.CommentStorageI
-	likeRepo     repo.LikeStorageI
+	db              postgres.DB
+	queryTimeout    time.Duration
+	userRepo        repo.UserStorageI
+	categoryRepo    repo.CategoryStorageI
+	postRepo        repo.PostStorageI
+	commentRepo     repo.CommentStorageI
+	likeRepo        repo.LikeStorageI
+	propertyRepo    repo.PropertyStorageI
+	roomRepo        repo.RoomStorageI
+	bookingRepo     repo.BookingStorageI
+	pricingRuleRepo repo.PricingRuleStorageI
+	paymentRepo     repo.PaymentStorageI
+	reviewRepo      repo.ReviewStorageI
+	reportRepo      repo.ReportStorageI
+	roomBlockRepo   repo.RoomBlockStorageI
+	sessionRepo     repo.SessionStorageI
+	twoFactorRepo   repo.TwoFactorStorageI
+	settingRepo     repo.SettingStorageI
+	identityRepo    repo.IdentityStorageI
+	apiKeyRepo      repo.APIKeyStorageI
+}
+
+// NewStoragePg creates the repositories of the database. Every query is
+// cancelled after queryTimeout, unless it is 0.
+func NewStoragePg(db *sqlx.DB, queryTimeout time.Duration) StorageI {
+	return newStoragePg(db, queryTimeout)
 }
 
-func NewStoragePg(db *sqlx.DB) StorageI {
+func newStoragePg(db postgres.DB, queryTimeout time.Duration) *storagePg {
 	return &storagePg{
-		userRepo:     postgres.NewUser(db),
-		categoryRepo: postgres.NewCategory(db),
-		postRepo:     postgres.NewPost(db),
-		commentRepo:  postgres.NewComment(db),
-		likeRepo:     postgres.NewLike(db),
+		db:              db,
+		queryTimeout:    queryTimeout,
+		userRepo:        postgres.NewUser(db, queryTimeout),
+		categoryRepo:    postgres.NewCategory(db, queryTimeout),
+		postRepo:        postgres.NewPost(db, queryTimeout),
+		commentRepo:     postgres.NewComment(db, queryTimeout),
+		likeRepo:        postgres.NewLike(db, queryTimeout),
+		propertyRepo:    postgres.NewProperty(db, queryTimeout),
+		roomRepo:        postgres.NewRoom(db, queryTimeout),
+		bookingRepo:     postgres.NewBooking(db, queryTimeout),
+		pricingRuleRepo: postgres.NewPricingRule(db, queryTimeout),
+		paymentRepo:     postgres.NewPayment(db, queryTimeout),
+		reviewRepo:      postgres.NewReview(db, queryTimeout),
+		reportRepo:      postgres.NewReport(db, queryTimeout),
+		roomBlockRepo:   postgres.NewRoomBlock(db, queryTimeout),
+		sessionRepo:     postgres.NewSession(db, queryTimeout),
+		twoFactorRepo:   postgres.NewTwoFactor(db, queryTimeout),
+		settingRepo:     postgres.NewSetting(db, queryTimeout),
+		identityRepo:    postgres.NewIdentity(db, queryTimeout),
+		apiKeyRepo:      postgres.NewAPIKey(db, queryTimeout),
 	}
 }
 
@@ -51,3 +108,69 @@ func (s *storagePg) Comment() repo.CommentStorageI {
 func (s *storagePg) Like() repo.LikeStorageI {
 	return s.likeRepo
 }
+
+func (s *storagePg) Property() repo.PropertyStorageI {
+	return s.propertyRepo
+}
+
+func (s *storagePg) Room() repo.RoomStorageI {
+	return s.roomRepo
+}
+
+func (s *storagePg) Booking() repo.BookingStorageI {
+	return s.bookingRepo
+}
+
+func (s *storagePg) PricingRule() repo.PricingRuleStorageI {
+	return s.pricingRuleRepo
+}
+
+func (s *storagePg) Payment() repo.PaymentStorageI {
+	return s.paymentRepo
+}
+
+func (s *storagePg) Review() repo.ReviewStorageI {
+	return s.reviewRepo
+}
+
+func (s *storagePg) Report() repo.ReportStorageI {
+	return s.reportRepo
+}
+
+func (s *storagePg) RoomBlock() repo.RoomBlockStorageI {
+	return s.roomBlockRepo
+}
+
+func (s *storagePg) Session() repo.SessionStorageI {
+	return s.sessionRepo
+}
+
+func (s *storagePg) TwoFactor() repo.TwoFactorStorageI {
+	return s.twoFactorRepo
+}
+
+func (s *storagePg) Setting() repo.SettingStorageI {
+	return s.settingRepo
+}
+
+func (s *storagePg) Identity() repo.IdentityStorageI {
+	return s.identityRepo
+}
+
+func (s *storagePg) APIKey() repo.APIKeyStorageI {
+	return s.apiKeyRepo
+}
+
+func (s *storagePg) WithTx(ctx context.Context, fn func(StorageI) error) error {
+	return postgres.RunInTx(ctx, s.db, func(tx postgres.DB) error {
+		return fn(newStoragePg(tx, s.queryTimeout))
+	})
+}
+
+// ToggleLike toggles the like of the user in a transaction of its own, so
+// that concurrent toggles of the user do not leave two likes or lose one
+func ToggleLike(ctx context.Context, s StorageI, like *repo.Like) error {
+	return s.WithTx(ctx, func(tx StorageI) error {
+		return tx.Like().CreateOrUpdate(ctx, like)
+	})
+}
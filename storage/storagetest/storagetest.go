@@ -0,0 +1,506 @@
+// Package storagetest is a conformance suite for the user, category, post,
+// comment and like repositories. Every backend runs it from its own tests,
+// so that they all behave the same.
+package storagetest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/bxcodec/faker/v4"
+	"github.com/google/uuid"
+	"github.com/ibrat-muslim/booking-service/storage/repo"
+	"github.com/stretchr/testify/require"
+)
+
+// Storage holds the repositories covered by the suite. It is implemented by
+// storage.StorageI.
+type Storage interface {
+	User() repo.UserStorageI
+	Category() repo.CategoryStorageI
+	Post() repo.PostStorageI
+	Comment() repo.CommentStorageI
+	Like() repo.LikeStorageI
+}
+
+// Run runs the suite against s. The storage may hold other rows, e.g. in a
+// shared database, as the suite only looks at rows it creates.
+func Run(t *testing.T, s Storage) {
+	t.Run("User", func(t *testing.T) { testUser(t, s) })
+	t.Run("UserSearch", func(t *testing.T) { testUserSearch(t, s) })
+	t.Run("UserBan", func(t *testing.T) { testUserBan(t, s) })
+	t.Run("Category", func(t *testing.T) { testCategory(t, s) })
+	t.Run("CategoryPagination", func(t *testing.T) { testCategoryPagination(t, s) })
+	t.Run("Post", func(t *testing.T) { testPost(t, s) })
+	t.Run("PostGetAll", func(t *testing.T) { testPostGetAll(t, s) })
+	t.Run("Comment", func(t *testing.T) { testComment(t, s) })
+	t.Run("Like", func(t *testing.T) { testLike(t, s) })
+}
+
+var ctx = context.Background()
+
+// missingID is the ID of rows which do not exist
+const missingID = int64(1) << 40
+
+// token returns a string found only in the rows of one test
+func token() string {
+	return uuid.NewString()[:8]
+}
+
+func createUser(t *testing.T, s Storage) *repo.User {
+	user, err := s.User().Create(ctx, &repo.User{
+		FirstName:   faker.FirstName(),
+		LastName:    faker.LastName(),
+		DateOfBirth: faker.Date(),
+		Email:       token() + faker.Email(),
+		Password:    faker.Password(),
+		Type:        repo.UserTypeGuest,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, user.ID)
+
+	t.Cleanup(func() {
+		_ = s.User().Delete(ctx, user.ID)
+	})
+
+	return user
+}
+
+func createCategory(t *testing.T, s Storage, title string) *repo.Category {
+	category, err := s.Category().Create(ctx, &repo.Category{
+		Title: title,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, category.ID)
+
+	t.Cleanup(func() {
+		_ = s.Category().Delete(ctx, category.ID)
+	})
+
+	return category
+}
+
+func createPost(t *testing.T, s Storage, user *repo.User, category *repo.Category, title string) *repo.Post {
+	post, err := s.Post().Create(ctx, &repo.Post{
+		Title:       title,
+		Description: faker.Sentence(),
+		UserID:      user.ID,
+		CategoryID:  category.ID,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, post.ID)
+
+	// Cleanups run last in first out, so the post goes before its author
+	t.Cleanup(func() {
+		_ = s.Post().Delete(ctx, post.ID)
+	})
+
+	return post
+}
+
+func testUser(t *testing.T, s Storage) {
+	u := createUser(t, s)
+	require.False(t, u.CreatedAt.IsZero())
+
+	user, err := s.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, u.Email, user.Email)
+	require.Equal(t, u.FirstName, user.FirstName)
+	require.Nil(t, user.BannedAt)
+
+	user, err = s.User().GetByEmail(ctx, u.Email)
+	require.NoError(t, err)
+	require.Equal(t, u.ID, user.ID)
+
+	_, err = s.User().GetByEmail(ctx, token()+faker.Email())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	u.FirstName = faker.FirstName()
+	err = s.User().Update(ctx, u)
+	require.NoError(t, err)
+
+	user, err = s.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, u.FirstName, user.FirstName)
+
+	err = s.User().UpdatePassword(ctx, &repo.UpdatePassword{
+		UserID:   u.ID,
+		Password: "new password",
+	})
+	require.NoError(t, err)
+
+	user, err = s.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Equal(t, "new password", user.Password)
+
+	err = s.User().Delete(ctx, u.ID)
+	require.NoError(t, err)
+
+	_, err = s.User().Get(ctx, u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.User().Update(ctx, u)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.User().Delete(ctx, u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testUserSearch(t *testing.T, s Storage) {
+	u := createUser(t, s)
+
+	users, err := s.User().GetAll(ctx, &repo.GetUsersParams{
+		Limit:  10,
+		Page:   1,
+		Search: u.Email[:8],
+	})
+	require.NoError(t, err)
+	require.Len(t, users.Users, 1)
+	require.Equal(t, int32(1), users.Count)
+	require.Equal(t, u.ID, users.Users[0].ID)
+
+	for _, search := range []string{
+		`'; DROP TABLE users; --`,
+		`%' OR '1'='1`,
+	} {
+		users, err := s.User().GetAll(ctx, &repo.GetUsersParams{
+			Limit:  10,
+			Page:   1,
+			Search: search,
+		})
+		require.NoError(t, err)
+		require.Empty(t, users.Users)
+		require.Zero(t, users.Count)
+	}
+
+	_, err = s.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+}
+
+func testUserBan(t *testing.T, s Storage) {
+	u := createUser(t, s)
+
+	err := s.User().Unban(ctx, u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.User().Ban(ctx, u.ID)
+	require.NoError(t, err)
+
+	user, err := s.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.NotNil(t, user.BannedAt)
+
+	err = s.User().Ban(ctx, u.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.User().Unban(ctx, u.ID)
+	require.NoError(t, err)
+
+	user, err = s.User().Get(ctx, u.ID)
+	require.NoError(t, err)
+	require.Nil(t, user.BannedAt)
+
+	err = s.User().Ban(ctx, missingID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testCategory(t *testing.T, s Storage) {
+	c := createCategory(t, s, token())
+
+	category, err := s.Category().Get(ctx, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, c.Title, category.Title)
+
+	c.Title = token()
+	err = s.Category().Update(ctx, c)
+	require.NoError(t, err)
+
+	category, err = s.Category().Get(ctx, c.ID)
+	require.NoError(t, err)
+	require.Equal(t, c.Title, category.Title)
+
+	err = s.Category().Delete(ctx, c.ID)
+	require.NoError(t, err)
+
+	_, err = s.Category().Get(ctx, c.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.Category().Update(ctx, c)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.Category().Delete(ctx, c.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testCategoryPagination(t *testing.T, s Storage) {
+	tok := token()
+
+	var categories []*repo.Category
+	for i := 0; i < 3; i++ {
+		categories = append(categories, createCategory(t, s, "Category "+tok))
+		time.Sleep(time.Millisecond)
+	}
+
+	// Search ignores case
+	first, err := s.Category().GetAll(ctx, &repo.GetCategoriesParams{
+		Limit:  2,
+		Page:   1,
+		Search: "category " + tok,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), first.Count)
+	require.Len(t, first.Categories, 2)
+
+	// Newest first
+	require.Equal(t, categories[2].ID, first.Categories[0].ID)
+	require.Equal(t, categories[1].ID, first.Categories[1].ID)
+
+	second, err := s.Category().GetAll(ctx, &repo.GetCategoriesParams{
+		Limit:  2,
+		Page:   2,
+		Search: tok,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), second.Count)
+	require.Len(t, second.Categories, 1)
+	require.Equal(t, categories[0].ID, second.Categories[0].ID)
+
+	// Wildcards are matched literally
+	none, err := s.Category().GetAll(ctx, &repo.GetCategoriesParams{
+		Limit:  2,
+		Page:   1,
+		Search: tok + "%",
+	})
+	require.NoError(t, err)
+	require.Empty(t, none.Categories)
+	require.Zero(t, none.Count)
+}
+
+func testPost(t *testing.T, s Storage) {
+	user := createUser(t, s)
+	category := createCategory(t, s, token())
+	p := createPost(t, s, user, category, token())
+
+	post, err := s.Post().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.Title, post.Title)
+	require.Nil(t, post.UpdatedAt)
+
+	// Getting a post counts a view
+	views := post.ViewsCount
+
+	post, err = s.Post().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, views+1, post.ViewsCount)
+
+	userID, err := s.Post().GetAuthorID(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, userID)
+
+	now := time.Now()
+	p.Title = token()
+	p.UpdatedAt = &now
+
+	err = s.Post().Update(ctx, p)
+	require.NoError(t, err)
+
+	post, err = s.Post().Get(ctx, p.ID)
+	require.NoError(t, err)
+	require.Equal(t, p.Title, post.Title)
+	require.NotNil(t, post.UpdatedAt)
+
+	err = s.Post().Delete(ctx, p.ID)
+	require.NoError(t, err)
+
+	_, err = s.Post().Get(ctx, p.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = s.Post().GetAuthorID(ctx, p.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.Post().Update(ctx, p)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.Post().Delete(ctx, p.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testPostGetAll(t *testing.T, s Storage) {
+	tok := token()
+	user := createUser(t, s)
+	other := createUser(t, s)
+	category := createCategory(t, s, token())
+
+	older := createPost(t, s, user, category, "Post "+tok)
+	time.Sleep(time.Millisecond)
+	newer := createPost(t, s, user, category, "Post "+tok)
+	time.Sleep(time.Millisecond)
+	otherPost := createPost(t, s, other, category, "Post "+tok)
+
+	posts, err := s.Post().GetAll(ctx, &repo.GetPostsParams{
+		Limit:  10,
+		Page:   1,
+		Search: tok,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), posts.Count)
+	require.Equal(t, otherPost.ID, posts.Posts[0].ID)
+
+	posts, err = s.Post().GetAll(ctx, &repo.GetPostsParams{
+		Limit:      10,
+		Page:       1,
+		Search:     tok,
+		UserID:     user.ID,
+		SortByDate: "asc",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), posts.Count)
+	require.Equal(t, older.ID, posts.Posts[0].ID)
+	require.Equal(t, newer.ID, posts.Posts[1].ID)
+
+	posts, err = s.Post().GetAll(ctx, &repo.GetPostsParams{
+		Limit:      10,
+		Page:       1,
+		CategoryID: category.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), posts.Count)
+
+	for _, search := range []string{
+		`'; DROP TABLE posts; --`,
+		`%' OR '1'='1`,
+	} {
+		posts, err := s.Post().GetAll(ctx, &repo.GetPostsParams{
+			Limit:      10,
+			Page:       1,
+			Search:     search,
+			CategoryID: category.ID,
+			SortByDate: "asc; DROP TABLE posts; --",
+		})
+		require.NoError(t, err)
+		require.Empty(t, posts.Posts)
+		require.Zero(t, posts.Count)
+	}
+}
+
+func testComment(t *testing.T, s Storage) {
+	author := createUser(t, s)
+	user := createUser(t, s)
+	category := createCategory(t, s, token())
+	post := createPost(t, s, author, category, token())
+
+	cm, err := s.Comment().Create(ctx, &repo.Comment{
+		PostID:      post.ID,
+		UserID:      user.ID,
+		Description: faker.Sentence(),
+	})
+	require.NoError(t, err)
+	require.NotZero(t, cm.ID)
+
+	comments, err := s.Comment().GetAll(ctx, &repo.GetCommentsParams{
+		Limit:  10,
+		Page:   1,
+		PostID: post.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), comments.Count)
+	require.Len(t, comments.Comments, 1)
+	require.Equal(t, cm.Description, comments.Comments[0].Description)
+	require.Equal(t, user.Email, comments.Comments[0].User.Email)
+	require.Equal(t, user.FirstName, comments.Comments[0].User.FirstName)
+
+	comments, err = s.Comment().GetAll(ctx, &repo.GetCommentsParams{
+		Limit:  10,
+		Page:   1,
+		PostID: post.ID,
+		UserID: author.ID,
+	})
+	require.NoError(t, err)
+	require.Empty(t, comments.Comments)
+	require.Zero(t, comments.Count)
+
+	userID, err := s.Comment().GetAuthorID(ctx, cm.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, userID)
+
+	now := time.Now()
+	cm.Description = faker.Sentence()
+	cm.UpdatedAt = &now
+
+	err = s.Comment().Update(ctx, cm)
+	require.NoError(t, err)
+
+	comments, err = s.Comment().GetAll(ctx, &repo.GetCommentsParams{
+		Limit:  10,
+		Page:   1,
+		PostID: post.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, cm.Description, comments.Comments[0].Description)
+	require.NotNil(t, comments.Comments[0].UpdatedAt)
+
+	err = s.Comment().Delete(ctx, cm.ID)
+	require.NoError(t, err)
+
+	_, err = s.Comment().GetAuthorID(ctx, cm.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.Comment().Update(ctx, cm)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = s.Comment().Delete(ctx, cm.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func testLike(t *testing.T, s Storage) {
+	author := createUser(t, s)
+	user := createUser(t, s)
+	category := createCategory(t, s, token())
+	post := createPost(t, s, author, category, token())
+
+	requireCounts := func(likes, dislikes int64) {
+		counts, err := s.Like().GetLikesDislikesCount(ctx, post.ID)
+		require.NoError(t, err)
+		require.Equal(t, likes, counts.LikesCount)
+		require.Equal(t, dislikes, counts.DislikesCount)
+	}
+
+	_, err := s.Like().Get(ctx, post.ID, user.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	requireCounts(0, 0)
+
+	like := &repo.Like{
+		PostID: post.ID,
+		UserID: user.ID,
+		Status: true,
+	}
+
+	err = s.Like().CreateOrUpdate(ctx, like)
+	require.NoError(t, err)
+
+	l, err := s.Like().Get(ctx, post.ID, user.ID)
+	require.NoError(t, err)
+	require.True(t, l.Status)
+	requireCounts(1, 0)
+
+	// The opposite status replaces the like
+	like.Status = false
+
+	err = s.Like().CreateOrUpdate(ctx, like)
+	require.NoError(t, err)
+
+	l, err = s.Like().Get(ctx, post.ID, user.ID)
+	require.NoError(t, err)
+	require.False(t, l.Status)
+	requireCounts(0, 1)
+
+	// The same status again removes it
+	err = s.Like().CreateOrUpdate(ctx, like)
+	require.NoError(t, err)
+
+	_, err = s.Like().Get(ctx, post.ID, user.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	requireCounts(0, 0)
+}